@@ -0,0 +1,127 @@
+package heroicons
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// AttributionFileName is the name of the attribution file written to the output directory
+const AttributionFileName = "ATTRIBUTION"
+
+// libraryLicense describes the license of an icon library
+type libraryLicense struct {
+	Name    string
+	License string
+	URL     string
+}
+
+// libraryLicenses maps the library names of the built-in sources to their licenses
+var libraryLicenses = map[string]libraryLicense{
+	"heroicons": {Name: "Heroicons", License: "MIT", URL: "https://github.com/tailwindlabs/heroicons"},
+	"lucide":    {Name: "Lucide", License: "ISC", URL: "https://github.com/lucide-icons/lucide"},
+	"feather":   {Name: "Feather", License: "MIT", URL: "https://github.com/feathericons/feather"},
+	"tabler":    {Name: "Tabler Icons", License: "MIT", URL: "https://github.com/tabler/tabler-icons"},
+}
+
+// headerComment returns Header as a Go comment followed by a blank line, or an empty
+// string if no header is set. Lines that are not already comments are prefixed with //.
+func (g *Generator) headerComment() string {
+	if strings.TrimSpace(g.Header) == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(g.Header, "\n"), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		switch {
+		case line == "":
+			b.WriteString("//\n")
+		case strings.HasPrefix(line, "//"):
+			b.WriteString(line + "\n")
+		default:
+			b.WriteString("// " + line + "\n")
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// executeToFile executes the template with data into the output file name, after the
+// header comment
+func (g *Generator) executeToFile(tmpl *template.Template, name string, data any) error {
+	var b bytes.Buffer
+	b.WriteString(g.headerComment())
+	if err := tmpl.Execute(&b, data); err != nil {
+		return err
+	}
+	return g.output().WriteFile(name, b.Bytes())
+}
+
+// sourceLibrary returns the license of the library icons are copied from and the text of
+// its LICENSE file, if found. The license is empty for sources other than the built-in
+// ones.
+func (g *Generator) sourceLibrary() (libraryLicense, string) {
+	source, ok := g.source().(layoutSource)
+	if !ok {
+		return libraryLicense{}, ""
+	}
+	for _, dir := range []string{source.root, path.Dir(source.root)} {
+		if text, err := fs.ReadFile(source.fsys, path.Join(dir, "LICENSE")); err == nil {
+			return libraryLicenses[source.library], string(text)
+		}
+	}
+	return libraryLicenses[source.library], ""
+}
+
+// writeAttribution writes the ATTRIBUTION file, listing the library the icons in
+// iconPaths were copied from with its version and license, and the custom icons, which
+// are not covered by that license
+func (g *Generator) writeAttribution(iconPaths map[string]string, custom map[string]string) error {
+	var libraryIcons, customIcons []string
+	for key := range iconPaths {
+		if _, ok := custom[key]; ok {
+			customIcons = append(customIcons, key)
+		} else {
+			libraryIcons = append(libraryIcons, key)
+		}
+	}
+	sort.Strings(libraryIcons)
+	sort.Strings(customIcons)
+
+	var b strings.Builder
+	b.WriteString("This package includes icons from the following sources.\n")
+
+	if len(libraryIcons) > 0 {
+		library, licenseText := g.sourceLibrary()
+		b.WriteString("\n")
+		switch {
+		case library.Name == "":
+			b.WriteString("Icons from a custom icon source\n")
+		case g.sourceVersion() != "":
+			fmt.Fprintf(&b, "%s %s\n", library.Name, g.sourceVersion())
+		default:
+			fmt.Fprintf(&b, "%s\n", library.Name)
+		}
+		if library.License != "" {
+			fmt.Fprintf(&b, "License: %s\n", library.License)
+			fmt.Fprintf(&b, "Source: %s\n", library.URL)
+		}
+		fmt.Fprintf(&b, "Icons: %s\n", strings.Join(libraryIcons, ", "))
+
+		if licenseText != "" {
+			fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(licenseText))
+		}
+	}
+
+	if len(customIcons) > 0 {
+		b.WriteString("\nCustom icons, not covered by the license above\n")
+		fmt.Fprintf(&b, "Icons: %s\n", strings.Join(customIcons, ", "))
+	}
+
+	return g.output().WriteFile(AttributionFileName, []byte(b.String()))
+}
@@ -0,0 +1,137 @@
+package heroicons
+
+import (
+	"strings"
+	"text/template"
+)
+
+// taggedTypes are the icon types that get a file guarded by a build tag
+var taggedTypes = []IconType{IconOutline, IconSolid, IconMini, IconMicro}
+
+// BuildTag returns the build tag that excludes the icons of the given type from a package
+// generated with BuildTags, such as heroicons_no_micro
+func BuildTag(iconType IconType) string {
+	return "heroicons_no_" + string(iconType)
+}
+
+const typeFileTemplate = `// Code generated by heroicons generator; DO NOT EDIT.
+
+//go:build !{{.Tag}}
+
+package {{.PackageName}}
+{{- if not .InlineSVG }}
+
+import "embed"
+
+//go:embed {{.IconsDir}}/{{.Type}}_*.svg
+var {{.Type}}IconFS embed.FS
+{{- end }}
+
+// Register the {{.Type}} icons, unless excluded with the {{.Tag}} build tag
+func init() {
+	registerIcons(map[string]string{
+{{- range $key, $path := .IconPaths }}
+		"{{ $key }}": "{{ $path }}",
+{{- end }}
+	}, {{ if .InlineSVG }}map[string]string{
+{{- range $path, $content := .InlineFiles }}
+		"{{ $path }}": {{ printf "%q" $content }},
+{{- end }}
+	}{{ else }}{{.Type}}IconFS{{ end }})
+}
+`
+
+// typeFile returns the name of the file holding the icons of the given type
+func (g *Generator) typeFile(iconType IconType) string {
+	return strings.TrimSuffix(g.providerFile(), ".go") + "_" + string(iconType) + ".go"
+}
+
+// splitTagged splits the manifest into the icons registered by the provider and the
+// icons of each type guarded by a build tag
+func splitTagged(iconPaths map[string]string) (map[string]string, map[IconType]map[string]string) {
+	untagged := make(map[string]string)
+	tagged := make(map[IconType]map[string]string)
+	for key, filename := range iconPaths {
+		iconType, _, _ := strings.Cut(key, "/")
+		if iconType == string(IconCustom) {
+			untagged[key] = filename
+			continue
+		}
+		if tagged[IconType(iconType)] == nil {
+			tagged[IconType(iconType)] = make(map[string]string)
+		}
+		tagged[IconType(iconType)][key] = filename
+	}
+	return untagged, tagged
+}
+
+// embedPatterns returns the //go:embed patterns of the provider for its icons
+func (g *Generator) embedPatterns(iconPaths map[string]string) string {
+	if !g.BuildTags {
+		if len(iconPaths) == 0 {
+			// A pattern matching no files does not compile
+			return customIconsDir + "/*.svg"
+		}
+		return g.iconsDirName() + "/*.svg " + customIconsDir + "/*.svg"
+	}
+
+	patterns := customIconsDir + "/*.svg"
+	for key := range iconPaths {
+		if strings.HasPrefix(key, string(IconCustom)+"/") {
+			patterns += " " + g.iconsDirName() + "/" + string(IconCustom) + "_*.svg"
+			break
+		}
+	}
+	return patterns
+}
+
+// generateTypeFiles writes a file per icon type, guarded by the type's build tag, that
+// registers the type's icons. Files of types without icons are removed.
+func (g *Generator) generateTypeFiles(tagged map[IconType]map[string]string, inlineFiles map[string]string) error {
+	tmpl, err := template.New("type").Parse(typeFileTemplate)
+	if err != nil {
+		return err
+	}
+
+	for _, iconType := range taggedTypes {
+		name := g.typeFile(iconType)
+		iconPaths := tagged[iconType]
+		if len(iconPaths) == 0 {
+			if err := g.output().RemoveAll(name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		files := make(map[string]string)
+		for _, filename := range iconPaths {
+			key := g.iconsDirName() + "/" + filename
+			if content, ok := inlineFiles[key]; ok {
+				files[key] = content
+			}
+		}
+
+		if err := g.executeToFile(tmpl, name, map[string]any{
+			"Tag":         BuildTag(iconType),
+			"Type":        iconType,
+			"PackageName": g.packageName(),
+			"IconsDir":    g.iconsDirName(),
+			"IconPaths":   iconPaths,
+			"InlineSVG":   g.InlineSVG,
+			"InlineFiles": files,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeTypeFiles removes the files generated by generateTypeFiles
+func (g *Generator) removeTypeFiles() error {
+	for _, iconType := range taggedTypes {
+		if err := g.output().RemoveAll(g.typeFile(iconType)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
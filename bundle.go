@@ -0,0 +1,143 @@
+package heroicons
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"sort"
+	"time"
+)
+
+// BundleFormat is the archive format of an icon bundle
+type BundleFormat string
+
+const (
+	BundleZip BundleFormat = "zip" // zip archive
+	BundleTar BundleFormat = "tar" // tar archive, optionally gzip-compressed when read
+)
+
+// bundleFile returns the name of the bundle written with Bundle
+func (g *Generator) bundleFile() string {
+	return "bundle." + string(g.Bundle)
+}
+
+// writeBundle writes the icons in iconPaths and the custom icons, including the missing
+// icon, to a bundle in the output directory, with an index of the icons. Entries are
+// sorted and timestamps fixed, so regenerating the same icons produces the same bundle.
+// Bundles in other formats, or any bundle when Bundle is not set, are removed.
+func (g *Generator) writeBundle(iconPaths map[string]string) error {
+	out := g.output()
+	for _, format := range []BundleFormat{BundleZip, BundleTar} {
+		if format != g.Bundle {
+			if err := out.RemoveAll("bundle." + string(format)); err != nil {
+				return err
+			}
+		}
+	}
+	if g.Bundle == "" {
+		return nil
+	}
+
+	index := BundleIndex{Version: g.sourceVersion(), Icons: make(map[string]string, len(iconPaths))}
+	names := make([]string, 0, len(iconPaths))
+	for key, filename := range iconPaths {
+		name := g.iconsDirName() + "/" + filename
+		index.Icons[key] = name
+		names = append(names, name)
+	}
+	customNames, err := fs.Glob(out, customIconsDir+"/*.svg")
+	if err != nil {
+		return err
+	}
+	names = append(names, customNames...)
+	sort.Strings(names)
+	names = slices.Compact(names)
+
+	indexData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	add, finish := g.bundleWriter(&buf)
+	if err := add(BundleIndexName, append(indexData, '\n')); err != nil {
+		return err
+	}
+	for _, name := range names {
+		content, err := fs.ReadFile(out, name)
+		if err != nil {
+			return err
+		}
+		if err := add(name, content); err != nil {
+			return err
+		}
+	}
+	if err := finish(); err != nil {
+		return err
+	}
+
+	return out.WriteFile(g.bundleFile(), buf.Bytes())
+}
+
+// bundleWriter returns functions adding a file to a bundle in the configured format
+// written to w, and finishing the bundle
+func (g *Generator) bundleWriter(w io.Writer) (add func(name string, content []byte) error, finish func() error) {
+	// The epoch rather than the zero time, which tar cannot encode
+	modTime := time.Unix(0, 0).UTC()
+
+	if g.Bundle == BundleTar {
+		tw := tar.NewWriter(w)
+		add = func(name string, content []byte) error {
+			header := &tar.Header{
+				Name:     name,
+				Mode:     0644,
+				Size:     int64(len(content)),
+				ModTime:  modTime,
+				Typeflag: tar.TypeReg,
+			}
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			_, err := tw.Write(content)
+			return err
+		}
+		return add, tw.Close
+	}
+
+	zw := zip.NewWriter(w)
+	add = func(name string, content []byte) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modTime})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(content)
+		return err
+	}
+	return add, zw.Close
+}
+
+// validateBundle checks the bundle format and the options that cannot be combined with
+// Bundle
+func (g *Generator) validateBundle() []error {
+	if g.Bundle == "" {
+		return nil
+	}
+
+	var errs []error
+	if g.Bundle != BundleZip && g.Bundle != BundleTar {
+		errs = append(errs, fmt.Errorf("unknown Bundle format %q (expected %q or %q)", g.Bundle, BundleZip, BundleTar))
+	}
+	if g.InlineSVG {
+		errs = append(errs, errors.New("Bundle and InlineSVG cannot be used together: the icons are either read from the bundle or held in the provider"))
+	}
+	if g.BuildTags {
+		errs = append(errs, errors.New("Bundle and BuildTags cannot be used together: icons in a bundle are not compiled in"))
+	}
+	return errs
+}
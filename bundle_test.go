@@ -0,0 +1,145 @@
+package heroicons
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// bundleReloadTest is run in a generated bundle package: it loads a bundle whose index
+// adds an icon and renames another
+const bundleReloadTest = `package icons
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/patrickward/go-heroicons/core"
+)
+
+func TestLoadBundleUsesIndex(t *testing.T) {
+	if !Has(core.IconRef{Name: "home", Type: core.IconOutline}) {
+		t.Fatal("the generated bundle does not hold outline/home")
+	}
+
+	path := filepath.Join(t.TempDir(), "next.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range map[string]string{
+		"index.json":         ` + "`" + `{"icons": {"outline/star": "icons/star.svg", "outline/house": "icons/home.svg"}}` + "`" + `,
+		"icons/star.svg":     "<svg><title>star</title></svg>",
+		"icons/home.svg":     "<svg><title>house</title></svg>",
+		"custom/missing.svg": "<svg><title>missing</title></svg>",
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadBundle(path); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = LoadBundle(BundlePath)
+	})
+	if got := strings.Join(Keys(), ","); got != "outline/house,outline/star" {
+		t.Errorf("Keys() = %s", got)
+	}
+	svg, err := RenderIcon("star", core.IconOutline, "")
+	if err != nil || !strings.Contains(string(svg), "<title>star</title>") {
+		t.Errorf("added icon: got %q, %v", svg, err)
+	}
+	svg, err = RenderIcon("house", core.IconOutline, "")
+	if err != nil || !strings.Contains(string(svg), "<title>house</title>") {
+		t.Errorf("renamed icon: got %q, %v", svg, err)
+	}
+	if Has(core.IconRef{Name: "home", Type: core.IconOutline}) {
+		t.Error("an icon removed from the bundle is still listed")
+	}
+}
+`
+
+// bundleReloadFileTest is run in a generated bundle package: it replaces the loaded bundle
+// file and reloads it
+const bundleReloadFileTest = `package icons
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/patrickward/go-heroicons/core"
+)
+
+func TestReloadReadsReplacedBundle(t *testing.T) {
+	original, err := os.ReadFile(BundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "bundle.zip")
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadBundle(path); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = LoadBundle(BundlePath)
+	})
+
+	// A file that is not a bundle is rejected and the current bundle kept
+	if err := os.WriteFile(path, []byte("broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Reload(); err == nil {
+		t.Error("Reload accepted a broken bundle")
+	}
+	if svg, err := RenderIcon("home", core.IconOutline, ""); err != nil || !strings.Contains(string(svg), "<title>home</title>") {
+		t.Errorf("after a failed reload: got %q, %v", svg, err)
+	}
+
+	// The replaced bundle is read by Reload
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if !Has(core.IconRef{Name: "bell", Type: core.IconSolid}) {
+		t.Error("the reloaded bundle does not hold solid/bell")
+	}
+}
+`
+
+func TestGeneratedBundleUsesIndex(t *testing.T) {
+	m := newGeneratedModule(t, func(g *Generator) { g.Bundle = BundleZip })
+	if err := os.WriteFile(filepath.Join(m.dir, "icons", "bundle_reload_test.go"), []byte(bundleReloadTest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.run(t, "test", "./icons")
+}
+
+func TestGeneratedBundleReload(t *testing.T) {
+	m := newGeneratedModule(t, func(g *Generator) { g.Bundle = BundleTar })
+	if err := os.WriteFile(filepath.Join(m.dir, "icons", "bundle_reload_test.go"), []byte(bundleReloadFileTest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.run(t, "test", "./icons")
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"flag"
+
+	"github.com/patrickward/go-heroicons"
+)
+
+// Exit codes, so CI scripts can branch on the kind of failure
+const (
+	exitOK      = 0 // success, or help was requested with -h
+	exitFailure = 1 // any failure not listed below
+	exitConfig  = 2 // invalid command, flags, config file or configuration
+	exitMissing = 3 // icons could not be found (with -fail-on-missing)
+	exitSource  = 4 // the heroicons release could not be downloaded
+	exitDrift   = 5 // the generated output or the icons changed (verify, lockfile)
+)
+
+// usageError is an error in the command line, such as an unknown command or flag
+type usageError struct {
+	error
+}
+
+func (e usageError) Unwrap() error {
+	return e.error
+}
+
+// exitCode returns the exit code for an error returned by a command. Requested help
+// (flag.ErrHelp) is not a failure, unless it is wrapped in a usageError because the
+// command line was incomplete.
+func exitCode(err error) int {
+	var usage usageError
+	switch {
+	case errors.As(err, &usage), errors.Is(err, heroicons.ErrInvalidConfig):
+		return exitConfig
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, heroicons.ErrMissingIcons):
+		return exitMissing
+	case errors.Is(err, heroicons.ErrSourceUnavailable):
+		return exitSource
+	case errors.Is(err, heroicons.ErrOutdated), errors.Is(err, heroicons.ErrLockfileMismatch):
+		return exitDrift
+	default:
+		return exitFailure
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/patrickward/go-heroicons"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"help", flag.ErrHelp, exitOK},
+		{"missing command", usageError{flag.ErrHelp}, exitConfig},
+		{"unknown flag", usageError{errors.New("flag provided but not defined: -x")}, exitConfig},
+		{"invalid config", fmt.Errorf("%w: bad", heroicons.ErrInvalidConfig), exitConfig},
+		{"missing icons", fmt.Errorf("%w: outline/x", heroicons.ErrMissingIcons), exitMissing},
+		{"source", heroicons.ErrSourceUnavailable, exitSource},
+		{"outdated", heroicons.ErrOutdated, exitDrift},
+		{"lockfile", heroicons.ErrLockfileMismatch, exitDrift},
+		{"other", errors.New("disk full"), exitFailure},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("%s: exitCode = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRunHelp(t *testing.T) {
+	for _, args := range [][]string{{"generate", "-h"}, {"size", "-help"}} {
+		if code := exitCode(run(args)); code != exitOK {
+			t.Errorf("%v: exit code %d, want %d", args, code, exitOK)
+		}
+	}
+	if code := exitCode(run(nil)); code != exitConfig {
+		t.Errorf("no command: exit code %d, want %d", code, exitConfig)
+	}
+}
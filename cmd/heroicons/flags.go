@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/patrickward/go-heroicons"
+)
+
+// generatorFlags holds the flags of commands that configure a generator
+type generatorFlags struct {
+	*flag.FlagSet
+
+	configPath      string
+	heroiconsPath   string
+	version         string
+	checksum        string
+	npm             bool
+	offline         bool
+	mirror          string
+	proxy           string
+	outputPath      string
+	packageName     string
+	icons           listFlag
+	exclude         listFlag
+	annotationDirs  listFlag
+	templateDirs    listFlag
+	templateFuncs   listFlag
+	failOnError     bool
+	failOnMissing   bool
+	clearIcons      bool
+	writeLockfile   bool
+	updateLockfile  bool
+	writeManifest   bool
+	missingIconPath string
+	jsonOutput      bool
+
+	// log receives the generator's messages. Defaults to stdout, or stderr with -json so
+	// stdout only holds the JSON result.
+	log io.Writer
+}
+
+// newGeneratorFlags defines the generator flags for the named command
+func newGeneratorFlags(name string) *generatorFlags {
+	f := &generatorFlags{FlagSet: flag.NewFlagSet(name, flag.ContinueOnError)}
+
+	f.StringVar(&f.configPath, "config", "", "path to a JSON config file; other flags override its settings")
+	f.StringVar(&f.heroiconsPath, "heroicons", "", "path to the heroicons repository")
+	f.StringVar(&f.version, "version", "", "heroicons release to download instead of using -heroicons")
+	f.StringVar(&f.checksum, "checksum", "", "expected SHA-256 checksum of the release archive")
+	f.BoolVar(&f.npm, "npm", false, "download -version from the npm registry instead of GitHub")
+	f.BoolVar(&f.offline, "offline", false, "fail instead of downloading -version if it is not in the download cache")
+	f.StringVar(&f.mirror, "mirror", "", "base URL of a GitHub or npm registry mirror to download -version from")
+	f.StringVar(&f.proxy, "proxy", "", "HTTP(S) proxy URL to download -version through (default from HTTPS_PROXY)")
+	f.StringVar(&f.outputPath, "out", ".", "output directory of the generated package")
+	f.StringVar(&f.packageName, "package", "icons", "name of the generated package")
+	f.Var(&f.icons, "icons", "comma-separated icons to include as type/name, where name may be a pattern such as arrow-* (repeatable)")
+	f.Var(&f.exclude, "exclude", "comma-separated icons or patterns to leave out as type/name (repeatable)")
+	f.Var(&f.annotationDirs, "annotations", "comma-separated directories to scan for //heroicons:use comments (repeatable)")
+	f.Var(&f.templateDirs, "templates", "comma-separated template directories to scan for icon calls (repeatable)")
+	f.Var(&f.templateFuncs, "template-funcs", "comma-separated template function names to look for (default \"icon\")")
+	f.BoolVar(&f.failOnError, "fail-on-error", false, "return an error for missing icons instead of rendering the missing icon")
+	f.BoolVar(&f.failOnMissing, "fail-on-missing", false, "exit with status 3 if any icon could not be found")
+	f.BoolVar(&f.clearIcons, "clear", false, "clear the icons directory before copying")
+	f.BoolVar(&f.writeLockfile, "lockfile", false, "write a heroicons.lock file")
+	f.BoolVar(&f.updateLockfile, "update-lockfile", false, "replace the lockfile even if locked icons changed")
+	f.BoolVar(&f.writeManifest, "manifest", false, "write a manifest.json file")
+	f.StringVar(&f.missingIconPath, "missing-icon", "", "path to an SVG file to use as the missing icon")
+	f.BoolVar(&f.jsonOutput, "json", false, "write the result as JSON to stdout")
+
+	return f
+}
+
+// Parse parses the command line, reporting invalid flags as usage errors
+func (f *generatorFlags) Parse(args []string) error {
+	if err := f.FlagSet.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{err}
+	}
+	return nil
+}
+
+// generator builds a generator from the parsed flags, loading the config file first if
+// one was given
+func (f *generatorFlags) generator() (*heroicons.Generator, error) {
+	g := &heroicons.Generator{}
+
+	// Without a config file every flag applies, including defaults. With a config file,
+	// only the flags given on the command line override its settings.
+	apply := f.VisitAll
+	if f.configPath != "" {
+		if err := g.LoadConfig(f.configPath); err != nil {
+			return nil, err
+		}
+		apply = f.Visit
+	}
+
+	var err error
+	apply(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "heroicons":
+			g.HeroiconsPath = f.heroiconsPath
+		case "version":
+			g.HeroiconsVersion = f.version
+		case "checksum":
+			g.HeroiconsChecksum = f.checksum
+		case "npm":
+			g.HeroiconsNPM = f.npm
+		case "offline":
+			g.Offline = f.offline
+		case "mirror":
+			g.HeroiconsMirror = f.mirror
+		case "proxy":
+			g.HeroiconsProxy = f.proxy
+		case "out":
+			g.OutputPath = f.outputPath
+		case "package":
+			g.PackageName = f.packageName
+		case "icons":
+			for _, key := range f.icons {
+				icon, parseErr := heroicons.ParseIconSet(key)
+				if parseErr != nil {
+					err = errors.Join(err, parseErr)
+					continue
+				}
+				g.Icons = append(g.Icons, icon)
+			}
+		case "exclude":
+			for _, key := range f.exclude {
+				icon, parseErr := heroicons.ParseIconSet(key)
+				if parseErr != nil {
+					err = errors.Join(err, parseErr)
+					continue
+				}
+				g.Exclude = append(g.Exclude, icon)
+			}
+		case "annotations":
+			g.AnnotationDirs = append(g.AnnotationDirs, f.annotationDirs...)
+		case "templates":
+			g.TemplateDirs = append(g.TemplateDirs, f.templateDirs...)
+		case "template-funcs":
+			g.TemplateFuncs = f.templateFuncs
+		case "fail-on-error":
+			g.FailOnError = f.failOnError
+		case "fail-on-missing":
+			g.FailOnMissing = f.failOnMissing
+		case "clear":
+			g.ClearIcons = f.clearIcons
+		case "lockfile":
+			g.WriteLockfile = f.writeLockfile
+		case "update-lockfile":
+			g.UpdateLockfile = f.updateLockfile
+		case "manifest":
+			g.WriteManifest = f.writeManifest
+		case "missing-icon":
+			if f.missingIconPath == "" {
+				return
+			}
+			svg, readErr := os.ReadFile(f.missingIconPath)
+			if readErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to read missing icon: %w", readErr))
+				return
+			}
+			g.MissingIconSVG = string(svg)
+		}
+	})
+	if err != nil {
+		return nil, usageError{err}
+	}
+
+	switch {
+	case f.log != nil:
+		g.Log = f.log
+	case f.jsonOutput:
+		g.Log = os.Stderr
+	}
+
+	return g, nil
+}
+
+// listFlag is a repeatable flag of comma-separated values
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
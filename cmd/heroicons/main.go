@@ -0,0 +1,299 @@
+// Command heroicons generates an embedded icons package from a heroicons repository.
+//
+// Usage:
+//
+//	heroicons generate -heroicons /path/to/heroicons -out ./icons -icons outline/home,solid/user
+//	heroicons generate -config heroicons.json
+//	heroicons watch -config heroicons.json
+//	heroicons verify -config heroicons.json
+//	heroicons diff -config heroicons.json -old ../heroicons-2.1 -new ../heroicons-2.2
+//	heroicons usage -config heroicons.json -renders counts.json -format csv
+//	heroicons size -config heroicons.json
+//	heroicons snippet -class size-5 outline/home
+//	heroicons plugins
+//
+// Output plugins add export formats without changes to this command. An output plugin
+// named foo is an executable heroicons-output-foo in PATH; "generate -plugin foo" runs it
+// after generating, with the manifest of the generated icons as JSON on stdin.
+//
+// Every command accepts -json to write its result as JSON to stdout, for build pipelines
+// and bots. Messages are then written to stderr, except for watch, which writes each
+// message as a JSON object on its own line. A command that fails writes an error object
+// holding the message, the exit status and any partial result instead.
+//
+// The exit status is 0 on success or when help is requested with -h, 2 for an invalid
+// command line (including a missing command) or configuration, 3 for icons that could not
+// be found (with -fail-on-missing), 4 when a heroicons release could not be downloaded, 5
+// when verify or the lockfile detect changes, and 1 for any other failure.
+//
+// It is intended to be run from a go:generate directive:
+//
+//	//go:generate go run github.com/patrickward/go-heroicons/cmd/heroicons generate -heroicons ../heroicons -out . -icons outline/home
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/patrickward/go-heroicons"
+)
+
+const usage = `Usage: heroicons <command> [flags]
+
+Commands:
+  generate    copy icons and generate the provider package
+  watch       generate, then regenerate when the config or scanned sources change
+  verify      check that the generated package is up to date with the config
+  diff        report how the configured icons differ between two heroicons versions
+  usage       report the call sites and render counts of each icon as CSV or JSON
+  size        report how much each icon and icon type adds to the generated package
+  snippet     print ready-to-paste usage snippets for icons
+  plugins     list the output plugins (heroicons-output-* executables) found in PATH
+
+Run "heroicons <command> -h" for the flags of a command.
+`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "heroicons: %v\n", err)
+			if jsonRequested(os.Args[1:]) {
+				_ = writeJSONError(err)
+			}
+		}
+		os.Exit(exitCode(err))
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return usageError{flag.ErrHelp}
+	}
+
+	switch args[0] {
+	case "generate":
+		return runGenerate(args[1:])
+	case "watch":
+		return runWatch(args[1:])
+	case "verify":
+		return runVerify(args[1:])
+	case "diff":
+		return runDiff(args[1:])
+	case "usage":
+		return runUsage(args[1:])
+	case "size":
+		return runSize(args[1:])
+	case "snippet":
+		return runSnippet(args[1:])
+	case "plugins":
+		return runPlugins(args[1:])
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(os.Stderr, usage)
+		return nil
+	default:
+		return usageError{fmt.Errorf("unknown command %q\n\n%s", args[0], usage)}
+	}
+}
+
+func runGenerate(args []string) error {
+	flags := newGeneratorFlags("generate")
+	dryRun := flags.Bool("dry-run", false, "report the files that would be written without writing anything")
+	showProgress := flags.Bool("progress", false, "show a progress bar on stderr while copying icons")
+	var pluginNames listFlag
+	flags.Var(&pluginNames, "plugin", "comma-separated output plugins to run after generating (repeatable); see \"heroicons plugins\"")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	g, err := flags.generator()
+	if err != nil {
+		return err
+	}
+	g.DryRun = *dryRun
+	plugins, err := lookupPlugins(pluginNames)
+	if err != nil {
+		return err
+	}
+	if *showProgress {
+		g.OnProgress = printProgress
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := g.GenerateReportContext(ctx)
+	if err == nil && !g.DryRun {
+		// With -json, stdout only holds the JSON result
+		var stdout io.Writer = os.Stdout
+		if flags.jsonOutput {
+			stdout = os.Stderr
+		}
+		err = invokePlugins(plugins, g, report, stdout)
+	}
+	if flags.jsonOutput && report != nil {
+		if err != nil {
+			return resultError{err: err, result: report}
+		}
+		return writeJSON(report)
+	}
+	return err
+}
+
+// printProgress draws a progress bar for the copied icons on stderr
+func printProgress(done, total int, current heroicons.IconSet) {
+	const width = 30
+	filled := width * done / total
+	fmt.Fprintf(os.Stderr, "\r[%s%s] %d/%d %-40s",
+		strings.Repeat("=", filled), strings.Repeat(" ", width-filled), done, total, current.Key())
+	if done == total {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+func runVerify(args []string) error {
+	flags := newGeneratorFlags("verify")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	g, err := flags.generator()
+	if err != nil {
+		return err
+	}
+	err = g.Verify()
+	if flags.jsonOutput {
+		result := struct {
+			UpToDate bool     `json:"up_to_date"`
+			Stale    []string `json:"stale"`
+			Leftover []string `json:"leftover"`
+		}{UpToDate: err == nil, Stale: []string{}, Leftover: []string{}}
+		var outdated *heroicons.OutdatedError
+		if errors.As(err, &outdated) {
+			result.Stale = append(result.Stale, outdated.Files...)
+			result.Leftover = append(result.Leftover, outdated.Leftover...)
+		}
+		if err != nil {
+			return resultError{err: err, result: result}
+		}
+		return writeJSON(result)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Generated package is up to date")
+	return nil
+}
+
+func runDiff(args []string) error {
+	flags := newGeneratorFlags("diff")
+	oldPath := flags.String("old", "", "path to the current version of the icon library (a heroicons repository unless the config sets a source)")
+	newPath := flags.String("new", "", "path to the version of the icon library to upgrade to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *oldPath == "" || *newPath == "" {
+		return usageError{errors.New("both -old and -new are required")}
+	}
+
+	g, err := flags.generator()
+	if err != nil {
+		return err
+	}
+
+	diff, err := g.Diff(*oldPath, *newPath)
+	if err != nil {
+		return err
+	}
+	if flags.jsonOutput {
+		return writeJSON(diff)
+	}
+	return diff.Write(os.Stdout)
+}
+
+func runWatch(args []string) error {
+	flags := newGeneratorFlags("watch")
+	interval := flags.Duration("interval", heroicons.DefaultWatchInterval, "how often to check for changes")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var log io.Writer = os.Stdout
+	if flags.jsonOutput {
+		log = newJSONLog(os.Stdout)
+		flags.log = log
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	_, _ = fmt.Fprintln(log, "Watching for changes, press Ctrl+C to stop")
+	return heroicons.Watch(ctx, *interval, flags.generator)
+}
+
+func runUsage(args []string) error {
+	flags := newGeneratorFlags("usage")
+	rendersPath := flags.String("renders", "", "path to a JSON file of render counts from the generated package's RenderCounts")
+	format := flags.String("format", "csv", "output format: csv or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "json" {
+		return usageError{fmt.Errorf("unknown format %q: expected csv or json", *format)}
+	}
+
+	g, err := flags.generator()
+	if err != nil {
+		return err
+	}
+
+	var renders map[string]int64
+	if *rendersPath != "" {
+		data, err := os.ReadFile(*rendersPath)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &renders); err != nil {
+			return fmt.Errorf("failed to parse render counts: %w", err)
+		}
+	}
+
+	report, err := g.Usage(renders)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" || flags.jsonOutput {
+		return report.WriteJSON(os.Stdout)
+	}
+	return report.WriteCSV(os.Stdout)
+}
+
+func runSize(args []string) error {
+	flags := newGeneratorFlags("size")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	g, err := flags.generator()
+	if err != nil {
+		return err
+	}
+
+	report, err := g.AnalyzeSize()
+	if err != nil {
+		return err
+	}
+	if flags.jsonOutput {
+		return writeJSON(report)
+	}
+	return report.Write(os.Stdout)
+}
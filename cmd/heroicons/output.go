@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// writeJSON writes v to stdout as indented JSON
+func writeJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// jsonErrorEnvelope is written to stdout when a command run with -json fails, so stdout
+// always holds JSON
+type jsonErrorEnvelope struct {
+	Error    string `json:"error"`
+	ExitCode int    `json:"exit_code"`
+	// Result is the partial result of the command, such as the report of a generation
+	// that could not find every icon
+	Result any `json:"result,omitempty"`
+}
+
+// resultError is an error returned along with the partial result of a command, which -json
+// writes in the error envelope
+type resultError struct {
+	err    error
+	result any
+}
+
+func (e resultError) Error() string {
+	return e.err.Error()
+}
+
+func (e resultError) Unwrap() error {
+	return e.err
+}
+
+// writeJSONError writes err, its exit code and any partial result to stdout as a JSON
+// error envelope
+func writeJSONError(err error) error {
+	envelope := jsonErrorEnvelope{Error: err.Error(), ExitCode: exitCode(err)}
+	var partial resultError
+	if errors.As(err, &partial) {
+		envelope.Result = partial.result
+	}
+	return writeJSON(envelope)
+}
+
+// jsonRequested reports whether the command line sets the -json flag. It is read before
+// the command's flags are parsed, so errors in the flags are reported as JSON too.
+func jsonRequested(args []string) bool {
+	requested := false
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name != "json" {
+			continue
+		}
+		if !hasValue {
+			requested = true
+			continue
+		}
+		requested, _ = strconv.ParseBool(value)
+	}
+	return requested
+}
+
+// jsonLog is a log writer that writes each message as a JSON object on its own line, for
+// commands that report as they go, such as watch
+type jsonLog struct {
+	encoder *json.Encoder
+}
+
+func newJSONLog(w io.Writer) jsonLog {
+	return jsonLog{encoder: json.NewEncoder(w)}
+}
+
+func (l jsonLog) Write(p []byte) (int, error) {
+	if err := l.encoder.Encode(map[string]string{"message": strings.TrimRight(string(p), "\n")}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/patrickward/go-heroicons"
+)
+
+func TestJSONRequested(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"verify", "-json"}, true},
+		{[]string{"verify", "-config", "heroicons.json", "--json"}, true},
+		{[]string{"snippet", "-json=true", "outline/home"}, true},
+		{[]string{"verify", "-json", "-json=false"}, false},
+		{[]string{"verify", "-jsonx"}, false},
+		{[]string{"snippet", "--", "-json"}, false},
+		{[]string{"size"}, false},
+	}
+	for _, tt := range tests {
+		if got := jsonRequested(tt.args); got != tt.want {
+			t.Errorf("jsonRequested(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestResultErrorExitCode(t *testing.T) {
+	err := resultError{err: heroicons.ErrOutdated, result: struct{}{}}
+	if got := exitCode(err); got != exitDrift {
+		t.Errorf("exitCode = %d, want %d", got, exitDrift)
+	}
+	if !errors.Is(err, heroicons.ErrOutdated) {
+		t.Error("resultError does not wrap its error")
+	}
+}
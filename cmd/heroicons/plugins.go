@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/patrickward/go-heroicons"
+)
+
+// pluginPrefix is the prefix of the executables run as output plugins. The plugin named
+// foo is the executable heroicons-output-foo in PATH.
+const pluginPrefix = "heroicons-output-"
+
+// findPlugins returns the names of the output plugins in PATH, sorted
+func findPlugins() []string {
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), pluginPrefix)
+			if !ok || name == "" || entry.IsDir() {
+				continue
+			}
+			if runtime.GOOS == "windows" {
+				if name, ok = strings.CutSuffix(name, ".exe"); !ok {
+					continue
+				}
+			} else if info, err := entry.Info(); err != nil || info.Mode()&0111 == 0 {
+				continue
+			}
+			seen[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// plugin is an output plugin found in PATH
+type plugin struct {
+	name string
+	path string
+}
+
+// lookupPlugins finds the executables of the named output plugins in PATH
+func lookupPlugins(names []string) ([]plugin, error) {
+	plugins := make([]plugin, len(names))
+	for i, name := range names {
+		path, err := exec.LookPath(pluginPrefix + name)
+		if err != nil {
+			return nil, usageError{fmt.Errorf("unknown output plugin %q: %s not found in PATH", name, pluginPrefix+name)}
+		}
+		plugins[i] = plugin{name: name, path: path}
+	}
+	return plugins, nil
+}
+
+// invokePlugins runs the output plugins in order, each receiving the manifest of the
+// generated icons as JSON on stdin. The output path and package name are passed in the
+// HEROICONS_OUTPUT_PATH and HEROICONS_PACKAGE environment variables.
+func invokePlugins(plugins []plugin, g *heroicons.Generator, report *heroicons.Report, stdout io.Writer) error {
+	if len(plugins) == 0 {
+		return nil
+	}
+
+	manifest, err := g.Manifest(report)
+	if err != nil {
+		return fmt.Errorf("failed to build manifest: %w", err)
+	}
+	payload, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	packageName := g.PackageName
+	if packageName == "" {
+		packageName = "icons"
+	}
+	for _, p := range plugins {
+		cmd := exec.Command(p.path)
+		cmd.Stdin = bytes.NewReader(payload)
+		cmd.Stdout = stdout
+		cmd.Stderr = os.Stderr
+		cmd.Env = append(os.Environ(),
+			"HEROICONS_OUTPUT_PATH="+report.OutputPath,
+			"HEROICONS_PACKAGE="+packageName)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("output plugin %s failed: %w", p.name, err)
+		}
+	}
+	return nil
+}
+
+func runPlugins(args []string) error {
+	flags := flag.NewFlagSet("plugins", flag.ContinueOnError)
+	jsonOutput := flags.Bool("json", false, "write the plugin names as JSON to stdout")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{err}
+	}
+
+	plugins := findPlugins()
+	if *jsonOutput {
+		return writeJSON(plugins)
+	}
+	if len(plugins) == 0 {
+		fmt.Printf("No output plugins found: add %s<name> executables to PATH\n", pluginPrefix)
+		return nil
+	}
+	for _, name := range plugins {
+		fmt.Println(name)
+	}
+	return nil
+}
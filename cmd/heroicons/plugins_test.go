@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/patrickward/go-heroicons"
+)
+
+// writePlugin writes an executable shell script named heroicons-output-<name> to dir
+func writePlugin(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, pluginPrefix+name), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	first, second := t.TempDir(), t.TempDir()
+	writePlugin(t, first, "figma", "")
+	writePlugin(t, second, "figma", "")
+	writePlugin(t, second, "storybook", "")
+	if err := os.WriteFile(filepath.Join(second, pluginPrefix+"notexec"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(second, pluginPrefix+"dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", first+string(filepath.ListSeparator)+second)
+
+	if got, want := findPlugins(), []string{"figma", "storybook"}; !slices.Equal(got, want) {
+		t.Errorf("findPlugins() = %v, want %v", got, want)
+	}
+
+	plugins, err := lookupPlugins([]string{"storybook", "figma"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plugins[1].path != filepath.Join(first, pluginPrefix+"figma") {
+		t.Errorf("figma found at %s, want the first PATH entry", plugins[1].path)
+	}
+	if _, err := lookupPlugins([]string{"sketch"}); exitCode(err) != exitConfig {
+		t.Errorf("unknown plugin: got %v, want a usage error", err)
+	}
+}
+
+func TestInvokePlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	received := filepath.Join(dir, "received.json")
+	writePlugin(t, dir, "record", `cat > "`+received+`"; echo "$HEROICONS_PACKAGE $HEROICONS_OUTPUT_PATH"`)
+	writePlugin(t, dir, "fail", "exit 3")
+	t.Setenv("PATH", dir+string(filepath.ListSeparator)+os.Getenv("PATH"))
+
+	g := &heroicons.Generator{
+		SourceFS: fstest.MapFS{
+			"optimized/24/outline/home.svg": &fstest.MapFile{Data: []byte(`<svg xmlns="http://www.w3.org/2000/svg"><path d="M0 0"/></svg>`)},
+		},
+		OutputPath:  "out",
+		Output:      &heroicons.MemFS{},
+		PackageName: "myicons",
+		Icons:       []heroicons.IconSet{{Name: "home", Type: heroicons.IconOutline}},
+		Log:         io.Discard,
+	}
+	report, err := g.GenerateReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plugins, err := lookupPlugins([]string{"record"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout strings.Builder
+	if err := invokePlugins(plugins, g, report, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	if got := stdout.String(); got != "myicons out\n" {
+		t.Errorf("plugin environment = %q, want the package and output path", got)
+	}
+
+	data, err := os.ReadFile(received)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest heroicons.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("plugin received %q: %v", data, err)
+	}
+	if len(manifest.Icons) != 1 || manifest.Icons[0].Key != "outline/home" {
+		t.Errorf("plugin received the manifest %+v", manifest)
+	}
+
+	plugins, err = lookupPlugins([]string{"fail", "record"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := invokePlugins(plugins, g, report, io.Discard); err == nil || !strings.Contains(err.Error(), "output plugin fail failed") {
+		t.Errorf("got %v, want the failing plugin's error", err)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/patrickward/go-heroicons"
+)
+
+// iconSnippets are the usage snippets of an icon
+type iconSnippets struct {
+	Icon     string              `json:"icon"`
+	Snippets []heroicons.Snippet `json:"snippets"`
+}
+
+func runSnippet(args []string) error {
+	flags := flag.NewFlagSet("snippet", flag.ContinueOnError)
+	pkg := flags.String("package", "icons", "name of the generated package")
+	class := flags.String("class", "size-6", "CSS classes of the rendered icon")
+	format := flags.String("format", "", "print only the snippet in this format: html/template, templ, gomponents or go")
+	jsonOutput := flags.Bool("json", false, "write the snippets as JSON to stdout")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: heroicons snippet [flags] type/name...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{err}
+	}
+	if flags.NArg() == 0 {
+		return usageError{errors.New("no icons given: expected type/name arguments such as outline/home")}
+	}
+
+	var results []iconSnippets
+	for _, key := range flags.Args() {
+		icon, err := heroicons.ParseIconSet(key)
+		if err != nil {
+			return usageError{err}
+		}
+		snippets := heroicons.Snippets(*pkg, icon, *class)
+		if *format != "" {
+			snippets = filterSnippets(snippets, heroicons.SnippetFormat(*format))
+			if len(snippets) == 0 {
+				return usageError{fmt.Errorf("unknown format %q: expected html/template, templ, gomponents or go", *format)}
+			}
+		}
+		results = append(results, iconSnippets{Icon: key, Snippets: snippets})
+	}
+
+	if *jsonOutput {
+		return writeJSON(results)
+	}
+	return writeSnippets(os.Stdout, results)
+}
+
+// filterSnippets returns the snippets in format
+func filterSnippets(snippets []heroicons.Snippet, format heroicons.SnippetFormat) []heroicons.Snippet {
+	var filtered []heroicons.Snippet
+	for _, snippet := range snippets {
+		if snippet.Format == format {
+			filtered = append(filtered, snippet)
+		}
+	}
+	return filtered
+}
+
+// writeSnippets writes the snippets of each icon as a table. A single snippet per icon is
+// written on its own, so it can be piped to the clipboard.
+func writeSnippets(w io.Writer, results []iconSnippets) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, result := range results {
+		if len(result.Snippets) == 1 {
+			fmt.Fprintln(tw, result.Snippets[0].Code)
+			continue
+		}
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintln(tw, result.Icon)
+		for _, snippet := range result.Snippets {
+			fmt.Fprintf(tw, "  %s\t%s\n", snippet.Format, snippet.Code)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/patrickward/go-heroicons"
+)
+
+func TestWriteSnippets(t *testing.T) {
+	icon := heroicons.IconSet{Name: "home", Type: heroicons.IconOutline}
+	all := heroicons.Snippets("icons", icon, "size-5")
+
+	var table strings.Builder
+	if err := writeSnippets(&table, []iconSnippets{{Icon: "outline/home", Snippets: all}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"outline/home\n", "  templ ", `icons.Icon("home", core.IconOutline, "size-5")`} {
+		if !strings.Contains(table.String(), want) {
+			t.Errorf("table does not contain %q:\n%s", want, table.String())
+		}
+	}
+
+	// A single snippet per icon is written on its own
+	var single strings.Builder
+	templ := filterSnippets(all, heroicons.SnippetTempl)
+	if err := writeSnippets(&single, []iconSnippets{{Icon: "outline/home", Snippets: templ}}); err != nil {
+		t.Fatal(err)
+	}
+	if want := templ[0].Code + "\n"; single.String() != want {
+		t.Errorf("got %q, want %q", single.String(), want)
+	}
+}
+
+func TestRunSnippetErrors(t *testing.T) {
+	for _, args := range [][]string{{"snippet"}, {"snippet", "home"}, {"snippet", "-format", "jsx", "outline/home"}} {
+		if code := exitCode(run(args)); code != exitConfig {
+			t.Errorf("%v: exit code %d, want %d", args, code, exitConfig)
+		}
+	}
+}
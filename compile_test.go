@@ -0,0 +1,102 @@
+package heroicons
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// compileModes are the generator configurations whose output must compile and pass go
+// vet, keyed by name
+var compileModes = map[string]func(g *Generator){
+	"embed":       func(g *Generator) {},
+	"inline":      func(g *Generator) { g.InlineSVG = true },
+	"typed":       func(g *Generator) { g.TypedFuncs = true },
+	"linked":      func(g *Generator) { g.LinkedFuncs = true },
+	"build tags":  func(g *Generator) { g.BuildTags = true },
+	"split types": func(g *Generator) { g.SplitTypes = true },
+	"bundle":      func(g *Generator) { g.Bundle = BundleZip },
+	"accessors":   func(g *Generator) { g.Accessors = true },
+}
+
+// generatedModule is a temporary module holding a generated icons package, for tests
+// running the go command on generated code
+type generatedModule struct {
+	dir   string
+	goCmd string
+}
+
+// newGeneratedModule generates the test icons, configured with configure, into the icons
+// package of a temporary module requiring this one. It skips the test in short mode or
+// without the go command.
+func newGeneratedModule(t *testing.T, configure func(g *Generator)) *generatedModule {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	goMod := fmt.Sprintf("module example.com/app\n\ngo 1.23\n\nrequire github.com/patrickward/go-heroicons v0.0.0\n\nreplace github.com/patrickward/go-heroicons => %s\n", root)
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source := testSource(map[string]string{"home": "home", "bell": "bell", "arrow-up": "arrow"})
+	source["optimized/24/solid/bell.svg"] = testSVG("solid bell")
+	source["optimized/20/solid/bell.svg"] = testSVG("mini bell")
+	g := &Generator{
+		SourceFS:      source,
+		OutputPath:    filepath.Join(dir, "icons"),
+		ImportPath:    "example.com/app/icons",
+		GenerateTests: true,
+		Icons: []IconSet{
+			{Name: "home", Type: IconOutline},
+			{Name: "bell", Type: IconOutline},
+			{Name: "arrow-up", Type: IconOutline},
+			{Name: "bell", Type: IconSolid},
+			{Name: "bell", Type: IconMini},
+		},
+		Log: io.Discard,
+	}
+	configure(g)
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	return &generatedModule{dir: dir, goCmd: goCmd}
+}
+
+// run runs the go command with args in the module, failing the test if it fails
+func (m *generatedModule) run(t *testing.T, args ...string) {
+	t.Helper()
+	cmd := exec.Command(m.goCmd, args...)
+	cmd.Dir = m.dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("go %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+}
+
+// TestGeneratedPackageCompiles generates a package in every mode into a temporary module
+// and builds and vets it with the go command
+func TestGeneratedPackageCompiles(t *testing.T) {
+	for name, configure := range compileModes {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			m := newGeneratedModule(t, configure)
+			m.run(t, "build", "./...")
+			m.run(t, "vet", "./...")
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package heroicons
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileConfig is the format of a generator config file. Paths are relative to the
+// directory containing the config file.
+type fileConfig struct {
+	HeroiconsPath      string            `json:"heroicons_path"`
+	HeroiconsVersion   string            `json:"heroicons_version"`
+	HeroiconsChecksum  string            `json:"heroicons_checksum"`
+	HeroiconsNPM       bool              `json:"heroicons_npm"`
+	Offline            bool              `json:"offline"`
+	HeroiconsMirror    string            `json:"heroicons_mirror"`
+	HeroiconsProxy     string            `json:"heroicons_proxy"`
+	OutputPath         string            `json:"output_path"`
+	PackageName        string            `json:"package_name"`
+	IconsDir           string            `json:"icons_dir"`
+	ProviderFile       string            `json:"provider_file"`
+	Icons              []string          `json:"icons"`
+	Exclude            []string          `json:"exclude"`
+	MissingIcon        string            `json:"missing_icon"`
+	MissingIconSVG     string            `json:"missing_icon_svg"`
+	FailOnError        bool              `json:"fail_on_error"`
+	FailOnMissing      bool              `json:"fail_on_missing"`
+	Deprecated         map[string]string `json:"deprecated"`
+	StrictDeprecations bool              `json:"strict_deprecations"`
+	ClearIcons         bool              `json:"clear_icons"`
+	Workers            int               `json:"workers"`
+	Sync               bool              `json:"sync"`
+	WriteLockfile      bool              `json:"write_lockfile"`
+	UpdateLockfile     bool              `json:"update_lockfile"`
+	WriteManifest      bool              `json:"write_manifest"`
+	Header             string            `json:"header"`
+	Attribution        bool              `json:"attribution"`
+	CSS                bool              `json:"css"`
+	InlineSVG          bool              `json:"inline_svg"`
+	Bundle             BundleFormat      `json:"bundle"`
+	Merge              bool              `json:"merge"`
+	AliasRenames       bool              `json:"alias_renames"`
+	Normalize          bool              `json:"normalize"`
+	ValidateIcons      bool              `json:"validate_icons"`
+	StripAttributes    []string          `json:"strip_attributes"`
+	GenerateCSPTest    bool              `json:"generate_csp_test"`
+	GenerateTests      bool              `json:"generate_tests"`
+	AnnotationDirs     []string          `json:"annotation_dirs"`
+	TemplateDirs       []string          `json:"template_dirs"`
+	TemplateFuncs      []string          `json:"template_funcs"`
+	DebugHandler       bool              `json:"debug_handler"`
+	PickerHandler      bool              `json:"picker_handler"`
+	ProfileLabels      bool              `json:"profile_labels"`
+	Accessors          bool              `json:"accessors"`
+	LinkedFuncs        bool              `json:"linked_funcs"`
+	BuildTags          bool              `json:"build_tags"`
+	SplitTypes         bool              `json:"split_types"`
+	TypedFuncs         bool              `json:"typed_funcs"`
+	ImportPath         string            `json:"import_path"`
+	PostGenerate       []string          `json:"post_generate"`
+	Webhook            string            `json:"webhook"`
+	CustomDirs         []struct {
+		Path string   `json:"path"`
+		Type IconType `json:"type"`
+	} `json:"custom_dirs"`
+	Source *struct {
+		Library string `json:"library"`
+		Path    string `json:"path"`
+	} `json:"source"`
+}
+
+// LoadConfig reads the generator configuration from a JSON config file, such as
+// heroicons.json. Icons are listed as type/name keys, and paths are resolved relative to
+// the directory containing the config file:
+//
+//	{
+//	  "heroicons_path": "../heroicons",
+//	  "output_path": "internal/icons",
+//	  "package_name": "icons",
+//	  "icons": ["outline/home", "solid/user"],
+//	  "missing_icon": "assets/missing.svg",
+//	  "fail_on_error": false
+//	}
+//
+// Unknown fields and invalid icons are reported as errors. The loaded configuration
+// replaces the generator's current settings.
+//
+// Errors wrap ErrInvalidConfig.
+func (g *Generator) LoadConfig(path string) error {
+	if err := g.loadConfig(path); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	return nil
+}
+
+// loadConfig reads the configuration from path into the generator
+func (g *Generator) loadConfig(path string) error {
+	if ext := filepath.Ext(path); ext != ".json" {
+		return fmt.Errorf("unsupported config format %q: only JSON config files are supported", ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var cfg fileConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return fmt.Errorf("%s: %w", path, describeJSONError(data, err))
+	}
+
+	dir := filepath.Dir(path)
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+
+	var icons []IconSet
+	var errs []error
+	for _, key := range cfg.Icons {
+		icon, err := ParseIconSet(key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		icons = append(icons, icon)
+	}
+
+	var exclude []IconSet
+	for _, key := range cfg.Exclude {
+		icon, err := ParseIconSet(key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("exclude: %w", err))
+			continue
+		}
+		exclude = append(exclude, icon)
+	}
+
+	missingIconSVG := cfg.MissingIconSVG
+	if cfg.MissingIcon != "" {
+		if missingIconSVG != "" {
+			errs = append(errs, errors.New("missing_icon and missing_icon_svg cannot both be set"))
+		}
+		svg, err := os.ReadFile(resolve(cfg.MissingIcon))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("missing_icon: %w", err))
+		}
+		missingIconSVG = string(svg)
+	}
+
+	var source IconSource
+	if cfg.Source != nil {
+		if newSource, ok := librarySources[cfg.Source.Library]; ok {
+			source = newSource(resolve(cfg.Source.Path))
+		} else {
+			errs = append(errs, fmt.Errorf("source: unknown library %q (expected heroicons, lucide, feather or tabler)", cfg.Source.Library))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	resolveAll := func(paths []string) []string {
+		resolved := make([]string, len(paths))
+		for i, p := range paths {
+			resolved[i] = resolve(p)
+		}
+		return resolved
+	}
+
+	g.configPath = path
+	g.HeroiconsPath = resolve(cfg.HeroiconsPath)
+	g.Source = source
+	g.HeroiconsVersion = cfg.HeroiconsVersion
+	g.HeroiconsChecksum = cfg.HeroiconsChecksum
+	g.HeroiconsNPM = cfg.HeroiconsNPM
+	g.Offline = cfg.Offline
+	g.HeroiconsMirror = cfg.HeroiconsMirror
+	g.HeroiconsProxy = cfg.HeroiconsProxy
+	g.OutputPath = resolve(cfg.OutputPath)
+	g.PackageName = cfg.PackageName
+	g.IconsDir = cfg.IconsDir
+	g.ProviderFile = cfg.ProviderFile
+	g.Icons = icons
+	g.Exclude = exclude
+	g.MissingIconSVG = missingIconSVG
+	g.FailOnError = cfg.FailOnError
+	g.FailOnMissing = cfg.FailOnMissing
+	g.Deprecated = cfg.Deprecated
+	g.StrictDeprecations = cfg.StrictDeprecations
+	g.ClearIcons = cfg.ClearIcons
+	g.Workers = cfg.Workers
+	g.Sync = cfg.Sync
+	g.WriteLockfile = cfg.WriteLockfile
+	g.UpdateLockfile = cfg.UpdateLockfile
+	g.WriteManifest = cfg.WriteManifest
+	g.Header = cfg.Header
+	g.Attribution = cfg.Attribution
+	g.CSS = cfg.CSS
+	g.InlineSVG = cfg.InlineSVG
+	g.Bundle = cfg.Bundle
+	g.Merge = cfg.Merge
+	g.AliasRenames = cfg.AliasRenames
+	g.Normalize = cfg.Normalize
+	g.ValidateIcons = cfg.ValidateIcons
+	g.StripAttributes = cfg.StripAttributes
+	g.GenerateCSPTest = cfg.GenerateCSPTest
+	g.GenerateTests = cfg.GenerateTests
+	g.AnnotationDirs = resolveAll(cfg.AnnotationDirs)
+	g.TemplateDirs = resolveAll(cfg.TemplateDirs)
+	g.TemplateFuncs = cfg.TemplateFuncs
+	g.DebugHandler = cfg.DebugHandler
+	g.PickerHandler = cfg.PickerHandler
+	g.ProfileLabels = cfg.ProfileLabels
+	g.Accessors = cfg.Accessors
+	g.LinkedFuncs = cfg.LinkedFuncs
+	g.BuildTags = cfg.BuildTags
+	g.SplitTypes = cfg.SplitTypes
+	g.TypedFuncs = cfg.TypedFuncs
+	g.ImportPath = cfg.ImportPath
+	g.PostGenerate = cfg.PostGenerate
+	g.Webhook = cfg.Webhook
+	g.CustomDirs = nil
+	for _, dir := range cfg.CustomDirs {
+		g.CustomDirs = append(g.CustomDirs, CustomSource{Path: resolve(dir.Path), Type: dir.Type})
+	}
+
+	return nil
+}
+
+// describeJSONError adds the line number to JSON syntax and type errors
+func describeJSONError(data []byte, err error) error {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err
+	}
+
+	line := 1 + strings.Count(string(data[:min(int(offset), len(data))]), "\n")
+	return fmt.Errorf("line %d: %w", line, err)
+}
@@ -0,0 +1,85 @@
+package heroicons
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes a config file with the given content to dir and returns its path
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "heroicons.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"assets/missing.svg": "<svg>missing</svg>"})
+	path := writeConfig(t, dir, `{
+  "heroicons_path": "../heroicons",
+  "output_path": "internal/icons",
+  "package_name": "myicons",
+  "icons": ["outline/home", "solid/user"],
+  "missing_icon": "assets/missing.svg",
+  "fail_on_error": true
+}`)
+
+	g := &Generator{ClearIcons: true, Icons: []IconSet{{Name: "stale", Type: IconMini}}}
+	if err := g.LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	if g.HeroiconsPath != filepath.Join(dir, "..", "heroicons") || g.OutputPath != filepath.Join(dir, "internal", "icons") {
+		t.Errorf("paths not resolved against the config directory: %q, %q", g.HeroiconsPath, g.OutputPath)
+	}
+	want := []IconSet{{Name: "home", Type: IconOutline}, {Name: "user", Type: IconSolid}}
+	if !slices.Equal(g.Icons, want) {
+		t.Errorf("Icons = %v, want %v", g.Icons, want)
+	}
+	if g.PackageName != "myicons" || g.MissingIconSVG != "<svg>missing</svg>" || !g.FailOnError {
+		t.Errorf("settings not loaded: %+v", g)
+	}
+	if g.ClearIcons {
+		t.Error("settings missing from the config file were kept")
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"unknown field", `{"icon": ["outline/home"]}`, []string{`unknown field "icon"`}},
+		{"syntax error", "{\n  \"icons\": [\"outline/home\",]\n}", []string{"line 2"}},
+		{"wrong type", "{\n\n  \"fail_on_error\": \"yes\"\n}", []string{"line 3", "fail_on_error"}},
+		{"invalid icons", `{"icons": ["home", "huge/bell"], "exclude": ["x"]}`, []string{`"home"`, "huge", "exclude:"}},
+		{"missing icon file", `{"missing_icon": "nope.svg"}`, []string{"missing_icon:", "nope.svg"}},
+		{"both missing icons", `{"missing_icon": "heroicons.json", "missing_icon_svg": "<svg/>"}`, []string{"cannot both be set"}},
+		{"unknown library", `{"source": {"library": "fontawesome", "path": "fa"}}`, []string{`unknown library "fontawesome"`}},
+	}
+	for _, tt := range tests {
+		path := writeConfig(t, t.TempDir(), tt.content)
+		err := (&Generator{}).LoadConfig(path)
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: got %v, want ErrInvalidConfig", tt.name, err)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: %v does not mention %q", tt.name, err, want)
+			}
+		}
+	}
+
+	err := (&Generator{}).LoadConfig(filepath.Join(t.TempDir(), "heroicons.yaml"))
+	if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), "only JSON") {
+		t.Errorf("YAML config: got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package heroicons
+
+import (
+	"github.com/patrickward/go-heroicons/core"
+	"github.com/patrickward/go-heroicons/iconbundle"
+	"github.com/patrickward/go-heroicons/iconhttp"
+)
+
+// The icon types and runtime helpers live in the core package, which generated packages
+// import instead of this one, the bundle reader in the iconbundle package and the HTTP
+// helpers in the iconhttp package. They are re-exported here so code configuring the
+// generator needs a single import. Application code that only renders icons can import
+// core directly to avoid linking the generator.
+
+type (
+	IconType      = core.IconType
+	IconSet       = core.IconSet
+	IconRef       = core.IconRef
+	RenderFunc    = core.RenderFunc
+	MissingPolicy = core.MissingPolicy
+	Request       = core.Request
+	Renderer      = core.Renderer
+	RendererFunc  = core.RendererFunc
+	Bundle        = iconbundle.Bundle
+	BundleIndex   = iconbundle.Index
+	PickerIcon    = iconhttp.PickerIcon
+	PickerPage    = iconhttp.PickerPage
+	Toggle        = iconhttp.Toggle
+	Assets        = iconhttp.Assets
+)
+
+const (
+	IconOutline = core.IconOutline
+	IconSolid   = core.IconSolid
+	IconMini    = core.IconMini
+	IconMicro   = core.IconMicro
+	IconCustom  = core.IconCustom
+
+	MissingDefault  = core.MissingDefault
+	MissingFallback = core.MissingFallback
+	MissingError    = core.MissingError
+	MissingEmpty    = core.MissingEmpty
+
+	BundleIndexName = iconbundle.IndexName
+
+	DefaultPickerPageSize = iconhttp.DefaultPickerPageSize
+)
+
+var (
+	SVGRenderer        = core.SVGRenderer
+	ParseIconSet       = core.ParseIconSet
+	SpriteID           = core.SpriteID
+	ValidateSVG        = core.ValidateSVG
+	WalkSVG            = core.WalkSVG
+	ErrPanic           = core.ErrPanic
+	CatchPanic         = core.CatchPanic
+	RecoverRender      = core.RecoverRender
+	ExternalReferences = core.ExternalReferences
+	IsAccessible       = core.IsAccessible
+	CallSite           = core.CallSite
+	SVGElements        = core.SVGElements
+	SVGAttributes      = core.SVGAttributes
+	OpenBundle         = iconbundle.Open
+	PickerHandler      = iconhttp.PickerHandler
+	FragmentHandler    = iconhttp.FragmentHandler
+	WriteFragment      = iconhttp.WriteFragment
+	NewAssets          = iconhttp.NewAssets
+	PipelineFuncs      = core.PipelineFuncs
+	SingleLine         = core.SingleLine
+	JSONString         = core.JSONString
+	WriteEvent         = core.WriteEvent
+)
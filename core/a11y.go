@@ -0,0 +1,21 @@
+package core
+
+import "strings"
+
+// IsAccessible reports whether the SVG markup is either hidden from assistive technology
+// (aria-hidden="true") or has an accessible name (aria-label, aria-labelledby or a title
+// element)
+func IsAccessible(svg string) bool {
+	root := svg
+	if start := strings.Index(svg, "<svg"); start >= 0 {
+		root = svg[start:]
+		if end := strings.Index(root, ">"); end >= 0 {
+			root = root[:end]
+		}
+	}
+
+	return strings.Contains(root, `aria-hidden="true"`) ||
+		strings.Contains(root, "aria-label=") ||
+		strings.Contains(root, "aria-labelledby=") ||
+		strings.Contains(svg, "<title")
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// callSiteSkipPackages are packages whose frames are never reported as the call site,
+// because they only forward calls made by templates
+var callSiteSkipPackages = []string{"text/template", "html/template", "reflect", "runtime"}
+
+// CallSite returns the file and line (such as "handlers/home.go:42") of the code that
+// called into the package calling CallSite, skipping frames of that package, of this
+// module and of the template engine. Generated providers use it to annotate rendered
+// icons in debug mode. Icons rendered from templates report the code that executed the
+// template, as template positions are not part of the call stack.
+func CallSite() string {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	caller, more := frames.Next()
+	skip := append([]string{funcPackage(caller.Function), "github.com/patrickward/go-heroicons"}, callSiteSkipPackages...)
+
+	for more {
+		var frame runtime.Frame
+		frame, more = frames.Next()
+		if skipFrame(frame.Function, skip) {
+			continue
+		}
+		dir, file := filepath.Split(frame.File)
+		return fmt.Sprintf("%s/%s:%d", filepath.Base(dir), file, frame.Line)
+	}
+	return ""
+}
+
+// skipFrame reports whether the function belongs to one of the packages (or their
+// subpackages)
+func skipFrame(function string, packages []string) bool {
+	pkg := funcPackage(function)
+	for _, skip := range packages {
+		if pkg == skip || strings.HasPrefix(pkg, skip+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// funcPackage returns the import path of the package of a fully qualified function name
+// as reported by the runtime, such as "example.com/app/icons.RenderIcon"
+func funcPackage(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
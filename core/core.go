@@ -0,0 +1,64 @@
+// Package core holds the icon types and runtime helpers shared by the heroicons generator
+// and the packages it generates. Generated packages import core rather than the
+// generator, so applications rendering icons do not link the generator, its downloads or
+// its HTTP client. The heroicons package re-exports everything declared here.
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IconType represents the different types of Heroicons
+type IconType string
+
+const (
+	IconOutline IconType = "outline" // 24px outline icons
+	IconSolid   IconType = "solid"   // 24px solid icons
+	IconMini    IconType = "mini"    // 20px solid icons
+	IconMicro   IconType = "micro"   // 16px solid icons
+	IconCustom  IconType = "custom"  // Custom icons (not part of Heroicons)
+)
+
+// Valid reports whether t is a known icon type
+func (t IconType) Valid() bool {
+	switch t {
+	case IconOutline, IconSolid, IconMini, IconMicro, IconCustom:
+		return true
+	}
+	return false
+}
+
+// IconSet defines an icon to be included in the project
+type IconSet struct {
+	Name string
+	Type IconType
+}
+
+// IconRef identifies an icon by name and type. It is the same type as IconSet, named for
+// APIs that refer to icons rather than configure them, and can be used as a map key.
+type IconRef = IconSet
+
+// Key returns the icon key in the form type/name, such as "outline/home"
+func (i IconSet) Key() string {
+	return string(i.Type) + "/" + i.Name
+}
+
+// ParseIconSet parses an icon key in the form type/name, such as "outline/home", into an
+// IconSet (or IconRef)
+func ParseIconSet(key string) (IconSet, error) {
+	// Accept Windows separators, such as keys completed by a shell from file paths
+	iconType, name, ok := strings.Cut(strings.ReplaceAll(key, `\`, "/"), "/")
+	if !ok || name == "" {
+		return IconSet{}, fmt.Errorf("invalid icon %q: expected type/name", key)
+	}
+	if !IconType(iconType).Valid() {
+		return IconSet{}, fmt.Errorf("invalid icon %q: unknown type %q", key, iconType)
+	}
+	return IconSet{Name: name, Type: IconType(iconType)}, nil
+}
+
+// SpriteID returns the id of the symbol for the given icon in the sprite sheet
+func SpriteID(name string, iconType IconType) string {
+	return fmt.Sprintf("%s-%s", iconType, name)
+}
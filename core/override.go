@@ -0,0 +1,24 @@
+package core
+
+import "sync/atomic"
+
+// Override holds a value set at runtime that takes precedence over a package variable.
+// Generated packages use it for their configuration variables: the variables are plain
+// values set during startup, and the Set functions change them safely while icons are
+// being rendered, for example from parallel tests. The zero value holds no value.
+type Override[T any] struct {
+	value atomic.Pointer[T]
+}
+
+// Set stores v, which Get returns from then on
+func (o *Override[T]) Set(v T) {
+	o.value.Store(&v)
+}
+
+// Get returns the value stored with Set, or fallback if none was stored
+func (o *Override[T]) Get(fallback T) T {
+	if v := o.value.Load(); v != nil {
+		return *v
+	}
+	return fallback
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"html/template"
+	"strings"
+)
+
+// pipelineIcon is an icon being built up by the pipeline template funcs
+type pipelineIcon struct {
+	icon  IconSet
+	class string
+}
+
+// PipelineFuncs returns template funcs for rendering icons in pipeline style, using
+// render to produce the final markup:
+//
+//	{{ "home" | outline | class "w-5 h-5" | render }}
+//
+// The outline, solid, mini, micro and custom funcs select the icon type, class adds
+// classes (and may be repeated), and render renders the icon.
+func PipelineFuncs(render RenderFunc) template.FuncMap {
+	ofType := func(iconType IconType) func(string) pipelineIcon {
+		return func(name string) pipelineIcon {
+			return pipelineIcon{icon: IconSet{Name: name, Type: iconType}}
+		}
+	}
+
+	return template.FuncMap{
+		"outline": ofType(IconOutline),
+		"solid":   ofType(IconSolid),
+		"mini":    ofType(IconMini),
+		"micro":   ofType(IconMicro),
+		"custom":  ofType(IconCustom),
+		"class": func(class string, p pipelineIcon) pipelineIcon {
+			p.class = strings.TrimSpace(p.class + " " + class)
+			return p
+		},
+		"render": func(p pipelineIcon) (template.HTML, error) {
+			return render(p.icon.Name, p.icon.Type, p.class)
+		},
+	}
+}
@@ -0,0 +1,36 @@
+package core
+
+// svgElements lists the SVG elements emitted by Heroicons and this package, including the
+// <symbol> elements of the sprite sheet and the <use> elements referencing them
+var svgElements = []string{
+	"svg", "g", "path", "circle", "ellipse", "line", "polyline", "polygon", "rect", "title",
+	"symbol", "use",
+}
+
+// svgAttributes lists the attributes emitted by Heroicons and this package
+var svgAttributes = []string{
+	"xmlns", "viewBox", "fill", "stroke", "stroke-width", "stroke-linecap", "stroke-linejoin",
+	"fill-rule", "clip-rule", "d", "cx", "cy", "r", "rx", "ry", "x", "y", "x1", "y1", "x2", "y2",
+	"points", "width", "height",
+	"aria-hidden", "aria-label", "role", "data-slot", "class", "id",
+	"href", "style",
+}
+
+// SVGElements returns the SVG elements emitted by Heroicons and this package. It can be
+// used to extend an HTML sanitizer policy so rendered icons are not stripped, for example
+// with bluemonday:
+//
+//	p := bluemonday.UGCPolicy()
+//	p.AllowElements(heroicons.SVGElements()...)
+//	p.AllowAttrs(heroicons.SVGAttributes()...).OnElements(heroicons.SVGElements()...)
+func SVGElements() []string {
+	return append([]string(nil), svgElements...)
+}
+
+// SVGAttributes returns the attributes emitted by Heroicons and this package on the
+// elements returned by SVGElements. href is only emitted with a fragment (#id) pointing
+// into the sprite sheet, and style only hides the sprite sheet, so a policy can restrict
+// them further.
+func SVGAttributes() []string {
+	return append([]string(nil), svgAttributes...)
+}
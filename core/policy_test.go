@@ -0,0 +1,38 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidateSVGSprite(t *testing.T) {
+	tests := []struct {
+		name string
+		svg  string
+	}{
+		{"sheet", `<svg xmlns="http://www.w3.org/2000/svg" aria-hidden="true" style="display:none"><symbol id="outline-home" viewBox="0 0 24 24"><path d="M0 0"/></symbol></svg>`},
+		{"reference", `<svg class="w-6" viewBox="0 0 24 24" aria-hidden="true"><use href="#outline-home"/></svg>`},
+	}
+	for _, tt := range tests {
+		if err := ValidateSVG([]byte(tt.svg)); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+	}
+
+	if err := ValidateSVG([]byte(`<svg><script>alert(1)</script></svg>`)); err == nil {
+		t.Error("script element was accepted")
+	}
+}
+
+func TestSVGPolicyCoversSprites(t *testing.T) {
+	for _, element := range []string{"symbol", "use"} {
+		if !slices.Contains(SVGElements(), element) {
+			t.Errorf("SVGElements does not contain %s", element)
+		}
+	}
+	for _, attr := range []string{"href", "style", "viewBox", "id"} {
+		if !slices.Contains(SVGAttributes(), attr) {
+			t.Errorf("SVGAttributes does not contain %s", attr)
+		}
+	}
+}
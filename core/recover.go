@@ -0,0 +1,31 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+)
+
+// ErrPanic is wrapped by the errors that CatchPanic and RecoverRender return for a panic
+// while rendering an icon
+var ErrPanic = errors.New("panic while rendering icon")
+
+// CatchPanic recovers a panic and stores it in *err as an error wrapping ErrPanic. Defer
+// it directly in a function with a named error result:
+//
+//	defer core.CatchPanic(&err)
+func CatchPanic(err *error) {
+	if v := recover(); v != nil {
+		*err = fmt.Errorf("%w: %v", ErrPanic, v)
+	}
+}
+
+// RecoverRender returns a RenderFunc calling render that returns a panic as an error
+// wrapping ErrPanic, so a buggy render func cannot take down the goroutine serving a
+// request
+func RecoverRender(render RenderFunc) RenderFunc {
+	return func(name string, iconType IconType, class string) (_ template.HTML, err error) {
+		defer CatchPanic(&err)
+		return render(name, iconType, class)
+	}
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"html/template"
+	"io"
+)
+
+// RenderFunc renders an icon with the given classes. The RenderIcon function of a
+// generated provider package satisfies this signature.
+type RenderFunc func(name string, iconType IconType, class string) (template.HTML, error)
+
+// MissingPolicy determines what happens when a requested icon is not found
+type MissingPolicy int
+
+const (
+	// MissingDefault follows the FailOnError setting of the generated package
+	MissingDefault MissingPolicy = iota
+	// MissingFallback renders the missing icon
+	MissingFallback
+	// MissingError returns an error
+	MissingError
+	// MissingEmpty renders nothing
+	MissingEmpty
+)
+
+// Request describes a single icon to render with the WriteIcon function of a generated
+// provider package
+type Request struct {
+	Name  string
+	Type  IconType
+	Class string
+	// OnMissing overrides the package-wide missing icon behavior for this request
+	OnMissing MissingPolicy
+}
+
+// Renderer draws a resolved icon in an output format other than SVG markup, such as a PNG
+// raster, a PDF or a terminal preview. The RenderWith function of a generated provider
+// package looks up the requested icon, applying deprecations, the missing icon behavior
+// and the request classes exactly as WriteIcon does, and passes the resulting SVG to the
+// renderer, so a backend only has to convert SVG to its format.
+type Renderer interface {
+	Render(w io.Writer, svg []byte, req Request) error
+}
+
+// RendererFunc adapts a function to the Renderer interface
+type RendererFunc func(w io.Writer, svg []byte, req Request) error
+
+// Render calls f(w, svg, req)
+func (f RendererFunc) Render(w io.Writer, svg []byte, req Request) error {
+	return f(w, svg, req)
+}
+
+// SVGRenderer writes the SVG unchanged, producing the same output as WriteIcon
+var SVGRenderer Renderer = RendererFunc(func(w io.Writer, svg []byte, _ Request) error {
+	_, err := w.Write(svg)
+	return err
+})
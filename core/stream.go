@@ -0,0 +1,37 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// SingleLine collapses the SVG markup onto a single line, so it can be sent as a single
+// server-sent event data field or embedded in other line-oriented protocols
+func SingleLine(svg template.HTML) string {
+	line := strings.Join(strings.Fields(string(svg)), " ")
+	return strings.ReplaceAll(line, "> <", "><")
+}
+
+// JSONString returns the SVG markup as a quoted JSON string. HTML characters are escaped,
+// so the result is safe to embed in JSON payloads and script blocks.
+func JSONString(svg template.HTML) string {
+	// Marshaling a string cannot fail
+	data, _ := json.Marshal(SingleLine(svg))
+	return string(data)
+}
+
+// WriteEvent writes the SVG markup as a server-sent event with the given event name. If
+// event is empty, the event line is omitted.
+func WriteEvent(w io.Writer, event string, svg template.HTML) error {
+	var b strings.Builder
+	if event != "" {
+		fmt.Fprintf(&b, "event: %s\n", event)
+	}
+	fmt.Fprintf(&b, "data: %s\n\n", SingleLine(svg))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
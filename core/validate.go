@@ -0,0 +1,98 @@
+package core
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ValidateSVG checks that the SVG content is well-formed XML with an <svg> root element
+// and contains only the elements returned by SVGElements
+func ValidateSVG(svg []byte) error {
+	return WalkSVG(svg, func(token xml.Token) error {
+		if t, ok := token.(xml.StartElement); ok && !slices.Contains(svgElements, t.Name.Local) {
+			return fmt.Errorf("element <%s> is not allowed", t.Name.Local)
+		}
+		return nil
+	})
+}
+
+// WalkSVG decodes the SVG content, checking that it is well-formed XML with a single <svg>
+// root element, and calls visit with every token. An error returned by visit stops the
+// walk and is returned.
+func WalkSVG(svg []byte, visit func(token xml.Token) error) error {
+	decoder := xml.NewDecoder(bytes.NewReader(svg))
+	decoder.Strict = true
+
+	depth := 0
+	seenRoot := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("malformed SVG: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				if seenRoot {
+					return errors.New("multiple root elements")
+				}
+				if t.Name.Local != "svg" {
+					return fmt.Errorf("root element is <%s>, not <svg>", t.Name.Local)
+				}
+				seenRoot = true
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(t)) > 0 {
+				return errors.New("unexpected text outside the root element")
+			}
+		}
+		if err := visit(token); err != nil {
+			return err
+		}
+	}
+
+	if !seenRoot {
+		return errors.New("missing <svg> root element")
+	}
+	return nil
+}
+
+var (
+	hrefPattern = regexp.MustCompile(`(?:href|src)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	urlPattern  = regexp.MustCompile(`url\(\s*['"]?([^'")]*)`)
+)
+
+// ExternalReferences returns every reference in the SVG content to a resource outside the
+// document (href, src and url() values that are not fragment identifiers). Such references
+// can trigger Content Security Policy violations when the icon is rendered.
+func ExternalReferences(svg []byte) []string {
+	var refs []string
+	for _, m := range hrefPattern.FindAllSubmatch(svg, -1) {
+		refs = appendExternal(refs, string(m[1])+string(m[2]))
+	}
+	for _, m := range urlPattern.FindAllSubmatch(svg, -1) {
+		refs = appendExternal(refs, string(m[1]))
+	}
+	return refs
+}
+
+func appendExternal(refs []string, ref string) []string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return refs
+	}
+	return append(refs, ref)
+}
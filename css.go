@@ -0,0 +1,70 @@
+package heroicons
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// CSSFileName is the name of the mask utility stylesheet written to the output directory
+const CSSFileName = "icons.css"
+
+// cssBase styles every element with an icon utility class. The icon is drawn with the
+// current text color through the mask.
+const cssBase = `[class^="hi-"], [class*=" hi-"] {
+  display: inline-block;
+  width: 1em;
+  height: 1em;
+  background-color: currentColor;
+  -webkit-mask: var(--hi-icon) no-repeat center / contain;
+  mask: var(--hi-icon) no-repeat center / contain;
+}
+`
+
+// CSSClass returns the utility class of the given icon in the generated stylesheet
+func CSSClass(name string, iconType IconType) string {
+	return fmt.Sprintf("hi-%s-%s", iconType, name)
+}
+
+// svgDataURIEscaper escapes the characters of an SVG document that are not allowed in a
+// url() data URI
+var svgDataURIEscaper = strings.NewReplacer(
+	"%", "%25", "#", "%23", "<", "%3C", ">", "%3E", `"`, "'", "\n", " ", "\r", "", "\t", " ",
+)
+
+// writeCSS writes a stylesheet with a mask-image utility class for each icon in the
+// manifest, such as .hi-outline-home. Outline icons are also available without the type,
+// such as .hi-home.
+func (g *Generator) writeCSS(iconPaths map[string]string) error {
+	keys := make([]string, 0, len(iconPaths))
+	for key := range iconPaths {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var css strings.Builder
+	css.WriteString("/* Code generated by heroicons generator; DO NOT EDIT. */\n\n")
+	css.WriteString(cssBase)
+
+	for _, key := range keys {
+		content, err := fs.ReadFile(g.output(), g.iconsDirName()+"/"+iconPaths[key])
+		if err != nil {
+			return err
+		}
+
+		icon, err := ParseIconSet(key)
+		if err != nil {
+			return err
+		}
+
+		selector := "." + CSSClass(icon.Name, icon.Type)
+		if icon.Type == IconOutline {
+			selector += ", .hi-" + icon.Name
+		}
+		uri := svgDataURIEscaper.Replace(strings.TrimSpace(string(content)))
+		fmt.Fprintf(&css, "\n%s {\n  --hi-icon: url(\"data:image/svg+xml,%s\");\n}\n", selector, uri)
+	}
+
+	return g.output().WriteFile(CSSFileName, []byte(css.String()))
+}
@@ -0,0 +1,84 @@
+package heroicons
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CustomSource is a directory of SVG icons that are not part of Heroicons, such as brand
+// or product icons. Each .svg file in Path is included as an icon of Type named after
+// the file.
+type CustomSource struct {
+	// Path is the directory containing the SVG files
+	Path string
+	// Type is the icon type the icons are registered under. Defaults to IconCustom.
+	Type IconType
+}
+
+// iconType returns the type the source's icons are registered under
+func (s CustomSource) iconType() IconType {
+	if s.Type == "" {
+		return IconCustom
+	}
+	return s.Type
+}
+
+// customIcons returns the SVG files of the custom sources keyed by icon key (type/name).
+// An icon that is provided by more than one source, or that is also one of the given
+// heroicons, is reported as a collision.
+func (g *Generator) customIcons(icons []IconSet) (map[string]string, error) {
+	sources := make(map[string]string)
+	for _, icon := range icons {
+		sources[fmt.Sprintf("%s/%s", icon.Type, icon.Name)] = "heroicons"
+	}
+
+	custom := make(map[string]string)
+	var collisions []string
+	for _, source := range g.CustomDirs {
+		entries, err := os.ReadDir(source.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read custom icons directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			filename := entry.Name()
+			path := filepath.Join(source.Path, filename)
+			if filepath.Ext(filename) != ".svg" || !isRegularFile(path) {
+				// Skip directories, and symlinks that are broken or do not point to a file
+				continue
+			}
+
+			key := fmt.Sprintf("%s/%s", source.iconType(), strings.TrimSuffix(filename, ".svg"))
+			if existing, ok := sources[key]; ok {
+				collisions = append(collisions, fmt.Sprintf("%s (%s and %s)", key, existing, path))
+				continue
+			}
+			sources[key] = path
+			custom[key] = path
+		}
+	}
+
+	if len(collisions) > 0 {
+		sort.Strings(collisions)
+		return nil, fmt.Errorf("icon names collide:\n%s", strings.Join(collisions, "\n"))
+	}
+	return custom, nil
+}
+
+// copyCustomIcons copies the icons of the custom sources into the icons directory, adding
+// each copied icon to iconPaths
+func (g *Generator) copyCustomIcons(custom map[string]string, iconPaths map[string]string, progress *progress) error {
+	for key, srcPath := range custom {
+		iconType, name, _ := strings.Cut(key, "/")
+		filename := fmt.Sprintf("%s_%s.svg", iconType, name)
+		if err := g.copyIcon(srcPath, g.iconsDirName()+"/"+filename, IconType(iconType)); err != nil {
+			return fmt.Errorf("failed to copy custom icon %s: %w", srcPath, err)
+		}
+		iconPaths[key] = filename
+		progress.step(IconSet{Name: name, Type: IconType(iconType)})
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package heroicons
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// VersionDiff reports how the configured icons differ between two heroicons versions
+type VersionDiff struct {
+	// Changed lists the keys of icons whose content changed
+	Changed []string `json:"changed"`
+	// Removed lists the keys of icons missing from the new version that could not be
+	// matched to a renamed icon
+	Removed []string `json:"removed"`
+	// Renamed maps the keys of icons missing from the new version to the keys of new
+	// icons with identical content
+	Renamed map[string]string `json:"renamed"`
+	// Added lists the keys of icons missing from the old version but present in the new
+	Added []string `json:"added"`
+	// Unchanged is the number of icons that are identical in both versions
+	Unchanged int `json:"unchanged"`
+}
+
+// Diff compares the configured icons (including those found in AnnotationDirs and
+// TemplateDirs) between the copies of the icon library at oldPath and newPath, so upgrades
+// can be reviewed before regenerating. The copies are read as the configured Source, or
+// as heroicons repositories (or extracted releases) when Source is not set; use
+// DiffSources for a Source that is not one of the supported libraries.
+func (g *Generator) Diff(oldPath, newPath string) (*VersionDiff, error) {
+	sources := make([]IconSource, 2)
+	for i, path := range []string{oldPath, newPath} {
+		if info, err := os.Stat(path); err != nil {
+			return nil, err
+		} else if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", path)
+		}
+		source, err := g.sourceAt(path)
+		if err != nil {
+			return nil, err
+		}
+		sources[i] = source
+	}
+	return g.DiffSources(sources[0], sources[1])
+}
+
+// DiffSources compares the configured icons between two versions of an icon library, like
+// Diff. Renamed icons are only detected if newSource implements IconLister.
+func (g *Generator) DiffSources(oldSource, newSource IconSource) (*VersionDiff, error) {
+	icons, err := g.resolveIcons()
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &VersionDiff{Renamed: make(map[string]string)}
+	for _, icon := range icons {
+		oldContent, oldErr := readSource(oldSource, icon)
+		newContent, newErr := readSource(newSource, icon)
+
+		switch {
+		case oldErr != nil && newErr != nil:
+			continue
+		case oldErr != nil:
+			diff.Added = append(diff.Added, icon.Key())
+		case newErr != nil:
+			if renamed, ok := findByContent(newSource, icon.Type, oldContent); ok {
+				diff.Renamed[icon.Key()] = renamed
+			} else {
+				diff.Removed = append(diff.Removed, icon.Key())
+			}
+		case !bytes.Equal(oldContent, newContent):
+			diff.Changed = append(diff.Changed, icon.Key())
+		default:
+			diff.Unchanged++
+		}
+	}
+
+	sort.Strings(diff.Changed)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Added)
+	return diff, nil
+}
+
+// readSource returns the content of the icon from the source
+func readSource(source IconSource, icon IconSet) ([]byte, error) {
+	r, err := source.Open(icon)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(r io.ReadCloser) {
+		_ = r.Close()
+	}(r)
+
+	return io.ReadAll(r)
+}
+
+// findByContent returns the key of the icon of the given type in source whose content
+// equals content. Sources that cannot list their icons never match.
+func findByContent(source IconSource, iconType IconType, content []byte) (string, bool) {
+	lister, ok := source.(IconLister)
+	if !ok {
+		return "", false
+	}
+	names, err := lister.List(iconType)
+	if err != nil {
+		return "", false
+	}
+
+	for _, name := range names {
+		icon := IconSet{Name: name, Type: iconType}
+		candidate, err := readSource(source, icon)
+		if err == nil && bytes.Equal(candidate, content) {
+			return icon.Key(), true
+		}
+	}
+	return "", false
+}
+
+// Write writes the diff in a human-readable form
+func (d *VersionDiff) Write(w io.Writer) error {
+	var b strings.Builder
+	for _, key := range d.Changed {
+		fmt.Fprintf(&b, "changed  %s\n", key)
+	}
+
+	renamed := make([]string, 0, len(d.Renamed))
+	for oldKey := range d.Renamed {
+		renamed = append(renamed, oldKey)
+	}
+	sort.Strings(renamed)
+	for _, oldKey := range renamed {
+		fmt.Fprintf(&b, "renamed  %s -> %s\n", oldKey, d.Renamed[oldKey])
+	}
+
+	for _, key := range d.Removed {
+		fmt.Fprintf(&b, "removed  %s\n", key)
+	}
+	for _, key := range d.Added {
+		fmt.Fprintf(&b, "added    %s\n", key)
+	}
+	fmt.Fprintf(&b, "%d changed, %d renamed, %d removed, %d added, %d unchanged\n",
+		len(d.Changed), len(d.Renamed), len(d.Removed), len(d.Added), d.Unchanged)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,106 @@
+package heroicons
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// writeTree writes files, keyed by slash-separated path, below dir
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDiffNPMPackage(t *testing.T) {
+	home := string(testSVG("home").Data)
+
+	// The old version is a repository clone, the new one the npm package layout
+	oldPath, newPath := t.TempDir(), t.TempDir()
+	writeTree(t, oldPath, map[string]string{
+		"optimized/24/outline/home.svg": home,
+		"optimized/24/outline/bell.svg": string(testSVG("bell").Data),
+		"optimized/24/outline/x.svg":    string(testSVG("x").Data),
+	})
+	writeTree(t, newPath, map[string]string{
+		"package.json":         `{"version": "2.2.0"}`,
+		"24/outline/house.svg": home,
+		"24/outline/bell.svg":  string(testSVG("bell v2").Data),
+		"24/outline/plus.svg":  string(testSVG("plus").Data),
+	})
+
+	g := &Generator{Icons: []IconSet{
+		{Name: "home", Type: IconOutline},
+		{Name: "bell", Type: IconOutline},
+		{Name: "x", Type: IconOutline},
+		{Name: "plus", Type: IconOutline},
+	}}
+	diff, err := g.Diff(oldPath, newPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := diff.Renamed["outline/home"]; got != "outline/house" {
+		t.Errorf("outline/home renamed to %q, want outline/house", got)
+	}
+	if !slices.Equal(diff.Changed, []string{"outline/bell"}) {
+		t.Errorf("changed = %v", diff.Changed)
+	}
+	if !slices.Equal(diff.Removed, []string{"outline/x"}) {
+		t.Errorf("removed = %v", diff.Removed)
+	}
+	if !slices.Equal(diff.Added, []string{"outline/plus"}) {
+		t.Errorf("added = %v", diff.Added)
+	}
+}
+
+func TestDiffConfiguredSource(t *testing.T) {
+	oldPath, newPath := t.TempDir(), t.TempDir()
+	writeTree(t, oldPath, map[string]string{
+		"icons/outline/home.svg": string(testSVG("home").Data),
+		"icons/filled/home.svg":  string(testSVG("filled home").Data),
+	})
+	writeTree(t, newPath, map[string]string{
+		"icons/outline/home.svg": string(testSVG("home v2").Data),
+		"icons/filled/house.svg": string(testSVG("filled home").Data),
+	})
+
+	g := &Generator{
+		Source: TablerSource(t.TempDir()),
+		Icons:  []IconSet{{Name: "home", Type: IconOutline}, {Name: "home", Type: IconSolid}},
+	}
+	diff, err := g.Diff(oldPath, newPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(diff.Changed, []string{"outline/home"}) {
+		t.Errorf("changed = %v", diff.Changed)
+	}
+	if got := diff.Renamed["solid/home"]; got != "solid/house" {
+		t.Errorf("solid/home renamed to %q, want solid/house", got)
+	}
+
+	// Other sources cannot be opened at a path, but can be compared directly
+	g.Source = struct{ IconSource }{HeroiconsFS(fstest.MapFS{})}
+	if _, err := g.Diff(oldPath, newPath); err == nil || !strings.Contains(err.Error(), "DiffSources") {
+		t.Errorf("got %v, want an error suggesting DiffSources", err)
+	}
+	diff, err = g.DiffSources(TablerSource(oldPath), TablerSource(newPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(diff.Changed, []string{"outline/home"}) {
+		t.Errorf("DiffSources changed = %v", diff.Changed)
+	}
+}
@@ -0,0 +1,206 @@
+package heroicons
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// dryRun resolves the icons and reports the files a generation would write, without
+// writing anything
+func (g *Generator) dryRun() (*Report, error) {
+	out := g.output()
+	dir := g.iconsDirName()
+
+	icons, err := g.resolveIcons()
+	if err != nil {
+		return nil, err
+	}
+
+	custom, err := g.customIcons(icons)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCaseCollisions(icons, custom); err != nil {
+		return nil, err
+	}
+
+	iconPaths := make(map[string]string)
+	var conflicts []string
+	if g.Merge {
+		if existing, err := existingIconPaths(out, dir); err == nil {
+			iconPaths = existing
+		}
+		icons, conflicts = g.mergeExisting(icons, iconPaths)
+	}
+
+	report := g.plan(icons, custom, iconPaths, g.ClearIcons)
+	report.Conflicts = conflicts
+
+	g.logDryRun(report)
+	return report, g.missingError(report.Missing)
+}
+
+// dryRunIcons reports the files GenerateIcons would write for icons, without writing
+// anything
+func (g *Generator) dryRunIcons(icons []IconSet) error {
+	iconPaths, err := existingIconPaths(g.output(), g.iconsDirName())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read icons directory: %w", err)
+	}
+	if iconPaths == nil {
+		iconPaths = make(map[string]string)
+	}
+
+	report := g.plan(icons, nil, iconPaths, false)
+	g.logDryRun(report)
+	return g.missingError(report.Missing)
+}
+
+// plan reports the files that would be created, overwritten and removed by generating
+// icons and custom into an output directory already holding iconPaths, which is updated
+// with the icons found. If clear is set, icons in the output that are not regenerated are
+// removed.
+func (g *Generator) plan(icons []IconSet, custom map[string]string, iconPaths map[string]string, clear bool) *Report {
+	out := g.output()
+	dir := g.iconsDirName()
+	existing, _ := existingIconPaths(out, dir)
+
+	var files, missing []string
+	for _, icon := range icons {
+		key := fmt.Sprintf("%s/%s", icon.Type, icon.Name)
+		if _, err := g.readIcon(icon); err != nil {
+			missing = append(missing, key)
+			continue
+		}
+		filename := fmt.Sprintf("%s_%s.svg", icon.Type, icon.Name)
+		iconPaths[key] = filename
+		files = append(files, dir+"/"+filename)
+	}
+	for key := range custom {
+		iconType, name, _ := strings.Cut(key, "/")
+		filename := fmt.Sprintf("%s_%s.svg", iconType, name)
+		iconPaths[key] = filename
+		files = append(files, dir+"/"+filename)
+	}
+
+	files = append(files,
+		customIconsDir+"/missing.svg",
+		g.providerFile())
+	if g.WriteLockfile || g.AliasRenames {
+		files = append(files, LockfileName)
+	}
+	if g.WriteManifest {
+		files = append(files, ManifestFileName)
+	}
+	if g.Attribution {
+		files = append(files, AttributionFileName)
+	}
+	if g.Sprite {
+		files = append(files, SpriteFileName)
+	}
+	if g.Bundle != "" {
+		files = append(files, g.bundleFile())
+	}
+	if g.CSS {
+		files = append(files, CSSFileName)
+	}
+	if g.GenerateCSPTest {
+		files = append(files, "csp_test.go")
+	}
+	if g.GenerateTests {
+		files = append(files, g.providerTestFile())
+	}
+	if g.LinkedFuncs {
+		files = append(files, linkedFuncsFile)
+	}
+
+	// Files of disabled outputs, or of types without icons, are removed
+	var stale []string
+	if !g.LinkedFuncs {
+		stale = append(stale, linkedFuncsFile)
+	}
+	for _, format := range []BundleFormat{BundleZip, BundleTar} {
+		if format != g.Bundle {
+			stale = append(stale, "bundle."+string(format))
+		}
+	}
+	_, tagged := splitTagged(iconPaths)
+	for _, iconType := range taggedTypes {
+		if g.BuildTags && len(tagged[iconType]) > 0 {
+			files = append(files, g.typeFile(iconType))
+		} else {
+			stale = append(stale, g.typeFile(iconType))
+		}
+	}
+	if g.TypedFuncs {
+		types := make(map[IconType]bool)
+		for key := range iconPaths {
+			iconType, _, _ := strings.Cut(key, "/")
+			types[IconType(iconType)] = true
+		}
+		for _, iconType := range typedPackageTypes {
+			if types[iconType] {
+				files = append(files, string(iconType)+"/"+typedFuncsFile)
+			} else {
+				stale = append(stale, string(iconType)+"/"+typedFuncsFile)
+			}
+		}
+	}
+	if clear {
+		for key, filename := range existing {
+			if _, ok := iconPaths[key]; !ok {
+				stale = append(stale, dir+"/"+filename)
+			}
+		}
+	}
+
+	report := &Report{
+		OutputPath: g.OutputPath,
+		Icons:      make([]string, 0, len(iconPaths)),
+		Missing:    missing,
+	}
+	for key := range iconPaths {
+		report.Icons = append(report.Icons, key)
+	}
+	sort.Strings(report.Icons)
+
+	for _, name := range files {
+		if _, err := fs.Stat(out, name); err == nil {
+			report.Overwritten = append(report.Overwritten, g.outputFile(name))
+		} else {
+			report.Created = append(report.Created, g.outputFile(name))
+		}
+	}
+	for _, name := range stale {
+		if _, err := fs.Stat(out, name); err == nil {
+			report.Removed = append(report.Removed, g.outputFile(name))
+		}
+	}
+	sort.Strings(report.Created)
+	sort.Strings(report.Overwritten)
+	sort.Strings(report.Removed)
+
+	return report
+}
+
+// logDryRun logs the changes a generation would make
+func (g *Generator) logDryRun(report *Report) {
+	g.logf("Dry run: no files were written\n")
+	g.logf("Icons that would be included: %d\n", len(report.Icons))
+	for _, list := range []struct {
+		title string
+		files []string
+	}{
+		{"Files that would be created", report.Created},
+		{"Files that would be overwritten", report.Overwritten},
+		{"Files that would be removed", report.Removed},
+	} {
+		if len(list.files) > 0 {
+			g.logf("%s:\n%s\n", list.title, strings.Join(list.files, "\n"))
+		}
+	}
+	g.logMissingIcons(report.Missing)
+}
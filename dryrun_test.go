@@ -0,0 +1,64 @@
+package heroicons
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDryRunPlan(t *testing.T) {
+	source := testSource(map[string]string{"home": "home", "bell": "bell"})
+	out := &MemFS{}
+	g := newTestGenerator(source, out, "home", "bell")
+	g.LinkedFuncs = true
+	g.Bundle = BundleZip
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	before := snapshot(t, out)
+
+	g = newTestGenerator(source, out, "home")
+	g.ClearIcons = true
+	g.DryRun = true
+	g.Bundle = BundleTar
+	report, err := g.GenerateReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := func(name string) string { return filepath.Join("icons", filepath.FromSlash(name)) }
+	wantRemoved := []string{file("bundle.zip"), file("icons/outline_bell.svg"), file("linked.go")}
+	if !slices.Equal(report.Removed, wantRemoved) {
+		t.Errorf("removed = %v, want %v", report.Removed, wantRemoved)
+	}
+	if !slices.Contains(report.Created, file("bundle.tar")) {
+		t.Errorf("created = %v, want it to contain the tar bundle", report.Created)
+	}
+
+	g.LinkedFuncs = true
+	report, err = g.GenerateReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(report.Overwritten, file("linked.go")) {
+		t.Errorf("overwritten = %v, want it to contain linked.go", report.Overwritten)
+	}
+
+	if err := g.GenerateIcons(IconSet{Name: "bell", Type: IconSolid}, IconSet{Name: "bell", Type: IconOutline}); err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(before, snapshot(t, out)) {
+		t.Error("a dry run changed the output")
+	}
+}
+
+// snapshot returns the content of every file of out
+func snapshot(t *testing.T, out *MemFS) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	for _, name := range out.Files() {
+		files[name] = readOutput(t, out, name)
+	}
+	return files
+}
@@ -0,0 +1,27 @@
+package heroicons
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors returned by the generator wrap one of these categories (or ErrOutdated and
+// ErrLockfileMismatch), so callers can tell failures apart with errors.Is
+var (
+	// ErrInvalidConfig is returned for an invalid configuration or config file
+	ErrInvalidConfig = errors.New("invalid configuration")
+	// ErrMissingIcons is returned when icons could not be found and FailOnMissing is set
+	ErrMissingIcons = errors.New("icons not found")
+	// ErrSourceUnavailable is returned when a heroicons release could not be downloaded
+	ErrSourceUnavailable = errors.New("icon source unavailable")
+)
+
+// missingError returns an error wrapping ErrMissingIcons that lists the missing icons, or
+// nil if none are missing or FailOnMissing is not set
+func (g *Generator) missingError(missing []string) error {
+	if !g.FailOnMissing || len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrMissingIcons, strings.Join(missing, ", "))
+}
@@ -0,0 +1,297 @@
+package heroicons
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// githubURL and npmRegistryURL are the default base URLs releases are downloaded from
+const (
+	githubURL      = "https://github.com"
+	npmRegistryURL = "https://registry.npmjs.org"
+)
+
+// releasePath is the path of a heroicons release archive on GitHub
+const releasePath = "/tailwindlabs/heroicons/archive/refs/tags/v%s.tar.gz"
+
+// npmPath is the path of a heroicons package tarball on the npm registry
+const npmPath = "/heroicons/-/heroicons-%s.tgz"
+
+// downloadTimeout bounds the download of a release archive, so an unresponsive server
+// fails generation instead of hanging it
+const downloadTimeout = 5 * time.Minute
+
+// downloadClient is the HTTP client release archives are downloaded with
+var downloadClient = &http.Client{Timeout: downloadTimeout}
+
+// releaseArchive describes where a heroicons version is downloaded from
+type releaseArchive struct {
+	// url is the archive URL, formatted with the version
+	url string
+	// cachePrefix is prepended to the version to name the cache directory
+	cachePrefix string
+	// keep reports whether a file of the archive, without its top-level directory, is
+	// extracted
+	keep func(name string) bool
+}
+
+// releaseArchive returns where HeroiconsVersion is downloaded from: the release archive
+// on GitHub, holding the repository, or with HeroiconsNPM the package tarball on npm,
+// holding the icons of each size at its root
+func (g *Generator) releaseArchive() releaseArchive {
+	if !g.HeroiconsNPM {
+		return releaseArchive{
+			url: g.mirrorURL(githubURL) + releasePath,
+			keep: func(name string) bool {
+				return name == "package.json" || strings.HasPrefix(name, "optimized/")
+			},
+		}
+	}
+
+	return releaseArchive{
+		url:         g.mirrorURL(npmRegistryURL) + npmPath,
+		cachePrefix: "npm-",
+		keep: func(name string) bool {
+			if name == "package.json" || name == "LICENSE" {
+				return true
+			}
+			for _, dir := range []string{"24/", "20/", "16/"} {
+				if strings.HasPrefix(name, dir) && path.Ext(name) == ".svg" {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// mirrorURL returns HeroiconsMirror without a trailing slash, or base if no mirror is set
+func (g *Generator) mirrorURL(base string) string {
+	if g.HeroiconsMirror == "" {
+		return base
+	}
+	return strings.TrimSuffix(g.HeroiconsMirror, "/")
+}
+
+// httpClient returns the client releases are downloaded with: HTTPClient, a client using
+// HeroiconsProxy, or the default client, which uses the proxy set in the environment
+func (g *Generator) httpClient() (*http.Client, error) {
+	if g.HTTPClient != nil {
+		return g.HTTPClient, nil
+	}
+	if g.HeroiconsProxy == "" {
+		return downloadClient, nil
+	}
+
+	proxy, err := url.Parse(g.HeroiconsProxy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proxy URL: %w", err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxy)
+	return &http.Client{Transport: transport, Timeout: downloadTimeout}, nil
+}
+
+// checksumFileName stores the archive checksum of a cached release
+const checksumFileName = ".checksum"
+
+// resolveSource downloads the configured heroicons release, if any, and uses the cached
+// copy as the icon source
+func (g *Generator) resolveSource(ctx context.Context) error {
+	if g.HeroiconsVersion == "" {
+		return nil
+	}
+
+	client, err := g.httpClient()
+	if err != nil {
+		return err
+	}
+
+	dir, sum, err := fetchRelease(ctx, client, g.releaseArchive(), g.HeroiconsVersion, g.HeroiconsChecksum, g.Offline)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%w: failed to fetch heroicons %s: %w", ErrSourceUnavailable, g.HeroiconsVersion, err)
+	}
+
+	g.downloadPath = dir
+	g.archiveChecksum = sum
+	return nil
+}
+
+// sourceDir returns the directory icons are copied from
+func (g *Generator) sourceDir() string {
+	if g.downloadPath != "" {
+		return g.downloadPath
+	}
+	return g.HeroiconsPath
+}
+
+// fetchRelease returns the cache directory of the given heroicons release, downloading
+// and extracting it first if needed. It returns the checksum of the release archive.
+// Releases are cached by version, and by version and checksum when a checksum is given,
+// so projects pinning different archives of the same version share the cache without
+// replacing each other's copy. With offline set, a release that is not cached is an
+// error instead of a download.
+func fetchRelease(ctx context.Context, client *http.Client, archive releaseArchive, version, checksum string, offline bool) (string, string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", "", err
+	}
+	dir := filepath.Join(cacheDir, "go-heroicons", archive.cachePrefix+version)
+
+	// A copy downloaded without a checksum is reused when it matches
+	candidates := []string{dir}
+	if checksum != "" {
+		dir += "-" + strings.ToLower(checksum)
+		candidates = []string{dir, candidates[0]}
+	}
+	for _, candidate := range candidates {
+		if sum, ok := cachedChecksum(candidate, checksum); ok {
+			return candidate, sum, nil
+		}
+	}
+
+	if offline {
+		return "", "", fmt.Errorf("not in the download cache %s and offline mode is set: run once with network access to cache it", dir)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(archive.url, version), nil)
+	if err != nil {
+		return "", "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("download failed: %s", resp.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return "", "", err
+	}
+
+	// Extract into a temporary directory and move it into place once complete, so an
+	// interrupted download never leaves a partial cache entry behind
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), filepath.Base(dir)+".tmp-")
+	if err != nil {
+		return "", "", err
+	}
+
+	defer func(tmp string) {
+		_ = os.RemoveAll(tmp)
+	}(tmp)
+
+	h := sha256.New()
+	if err := extractRelease(io.TeeReader(resp.Body, h), tmp, archive.keep); err != nil {
+		return "", "", err
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	if checksum != "" && !strings.EqualFold(sum, checksum) {
+		return "", "", fmt.Errorf("checksum mismatch: expected %s, got %s", checksum, sum)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmp, checksumFileName), []byte(sum+"\n"), 0644); err != nil {
+		return "", "", err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return "", "", err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return "", "", err
+	}
+
+	return dir, sum, nil
+}
+
+// cachedChecksum returns the archive checksum of the release cached in dir, and whether
+// it is cached and matches checksum, if one is given
+func cachedChecksum(dir, checksum string) (string, bool) {
+	cached, err := os.ReadFile(filepath.Join(dir, checksumFileName))
+	if err != nil {
+		return "", false
+	}
+	sum := strings.TrimSpace(string(cached))
+	return sum, checksum == "" || strings.EqualFold(sum, checksum)
+}
+
+// extractRelease extracts the files of a heroicons release archive that keep reports into
+// dir, dropping the archive's top-level directory
+func extractRelease(r io.Reader, dir string, keep func(name string) bool) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// Reject entries that would escape the archive's directory, then strip the
+		// top-level directory (heroicons-<version>/ or package/)
+		name := path.Clean(header.Name)
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return fmt.Errorf("invalid path in archive: %s", header.Name)
+		}
+		_, name, ok := strings.Cut(name, "/")
+		if !ok || !keep(name) {
+			continue
+		}
+
+		dest := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+		if err := writeFileFrom(dest, tr); err != nil {
+			return err
+		}
+	}
+
+	// Read the rest of the stream so the checksum covers the whole archive
+	_, err = io.Copy(io.Discard, r)
+	return err
+}
+
+// writeFileFrom writes the contents of r to a new file at path
+func writeFileFrom(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
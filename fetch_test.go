@@ -0,0 +1,280 @@
+package heroicons
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// testArchive returns a gzipped tar archive holding the given files, keyed by path
+func testArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// checksumOf returns the SHA-256 checksum (hex) of data
+func checksumOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// releaseServer serves a release archive and counts the requests it receives
+type releaseServer struct {
+	*httptest.Server
+	requests atomic.Int32
+}
+
+// newReleaseServer starts a server serving archive at path, and a user cache directory
+// for the downloads of the test
+func newReleaseServer(t *testing.T, path string, archive []byte) *releaseServer {
+	t.Helper()
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+	t.Setenv("HOME", cache)
+	t.Setenv("LocalAppData", cache)
+
+	s := &releaseServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(archive)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// fetch downloads version with the archive layout of g from the server
+func (s *releaseServer) fetch(g *Generator, version, checksum string, offline bool) (string, string, error) {
+	g.HeroiconsMirror = s.URL
+	return fetchRelease(context.Background(), s.Client(), g.releaseArchive(), version, checksum, offline)
+}
+
+// githubArchive returns a release archive as published on GitHub, holding home
+func githubArchive(t *testing.T) []byte {
+	return testArchive(t, map[string]string{
+		"heroicons-2.2.0/package.json":                    `{"version": "2.2.0"}`,
+		"heroicons-2.2.0/README.md":                       "not extracted",
+		"heroicons-2.2.0/optimized/24/outline/home.svg":   string(testSVG("home").Data),
+		"heroicons-2.2.0/src/24/outline/home.svg":         "not extracted",
+		"heroicons-2.2.0/optimized/24/solid/home.svg":     string(testSVG("solid home").Data),
+		"heroicons-2.2.0/optimized/20/solid/arrow-up.svg": string(testSVG("arrow").Data),
+	})
+}
+
+func TestGenerateDownloadsRelease(t *testing.T) {
+	archive := githubArchive(t)
+	s := newReleaseServer(t, "/tailwindlabs/heroicons/archive/refs/tags/v2.2.0.tar.gz", archive)
+
+	out := &MemFS{}
+	g := newTestGenerator(nil, out, "home")
+	g.HeroiconsVersion = "2.2.0"
+	g.HeroiconsChecksum = checksumOf(archive)
+	g.HeroiconsMirror = s.URL + "/"
+	g.HTTPClient = s.Client()
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(readOutput(t, out, "icons/outline_home.svg"), "<title>home</title>") {
+		t.Error("the downloaded icon was not copied")
+	}
+
+	dir, _, err := s.fetch(&Generator{}, "2.2.0", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"README.md", "src"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			t.Errorf("%s was extracted", name)
+		}
+	}
+}
+
+func TestFetchReleaseChecksumMismatch(t *testing.T) {
+	s := newReleaseServer(t, "/tailwindlabs/heroicons/archive/refs/tags/v2.2.0.tar.gz", githubArchive(t))
+
+	wrong := strings.Repeat("0", 64)
+	_, _, err := s.fetch(&Generator{}, "2.2.0", wrong, false)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("got %v, want a checksum mismatch", err)
+	}
+
+	// The rejected download is not cached
+	cache, _ := os.UserCacheDir()
+	entries, _ := os.ReadDir(filepath.Join(cache, "go-heroicons"))
+	if len(entries) != 0 {
+		t.Errorf("cache holds %d entries after a rejected download", len(entries))
+	}
+}
+
+func TestFetchReleaseRejectsPathTraversal(t *testing.T) {
+	for _, name := range []string{"../optimized/24/outline/home.svg", "/optimized/24/outline/home.svg", "heroicons-2.2.0/../../optimized/x.svg"} {
+		archive := testArchive(t, map[string]string{name: "escaped"})
+		s := newReleaseServer(t, "/tailwindlabs/heroicons/archive/refs/tags/v2.2.0.tar.gz", archive)
+
+		_, _, err := s.fetch(&Generator{}, "2.2.0", "", false)
+		if err == nil || !strings.Contains(err.Error(), "invalid path in archive") {
+			t.Errorf("%s: got %v, want an invalid path error", name, err)
+		}
+	}
+}
+
+func TestFetchReleaseDownloadFailure(t *testing.T) {
+	s := newReleaseServer(t, "/elsewhere", nil)
+
+	out := &MemFS{}
+	g := newTestGenerator(nil, out, "home")
+	g.HeroiconsVersion = "2.2.0"
+	g.HeroiconsMirror = s.URL
+	g.HTTPClient = s.Client()
+	err := g.Generate()
+	if !errors.Is(err, ErrSourceUnavailable) || !strings.Contains(err.Error(), "404") {
+		t.Errorf("got %v, want ErrSourceUnavailable with the status", err)
+	}
+}
+
+func TestFetchReleaseCache(t *testing.T) {
+	archive := githubArchive(t)
+	sum := checksumOf(archive)
+	s := newReleaseServer(t, "/tailwindlabs/heroicons/archive/refs/tags/v2.2.0.tar.gz", archive)
+
+	// A download without a checksum is cached by version
+	plain, got, err := s.fetch(&Generator{}, "2.2.0", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != sum || filepath.Base(plain) != "2.2.0" {
+		t.Errorf("got %s with checksum %s", plain, got)
+	}
+
+	// and reused for a matching checksum without downloading it again
+	dir, _, err := s.fetch(&Generator{}, "2.2.0", strings.ToUpper(sum), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != plain || s.requests.Load() != 1 {
+		t.Errorf("got %s after %d requests, want the cached %s", dir, s.requests.Load(), plain)
+	}
+
+	// A different checksum downloads again and is keyed by version and checksum
+	if err := os.WriteFile(filepath.Join(plain, checksumFileName), []byte("other\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dir, _, err = s.fetch(&Generator{}, "2.2.0", sum, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(dir) != "2.2.0-"+sum || s.requests.Load() != 2 {
+		t.Errorf("got %s after %d requests", dir, s.requests.Load())
+	}
+
+	// which is then a cache hit, also offline
+	again, _, err := s.fetch(&Generator{}, "2.2.0", sum, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != dir || s.requests.Load() != 2 {
+		t.Errorf("got %s after %d requests, want the cached %s", again, s.requests.Load(), dir)
+	}
+}
+
+func TestFetchReleaseOfflineColdCache(t *testing.T) {
+	s := newReleaseServer(t, "/tailwindlabs/heroicons/archive/refs/tags/v2.2.0.tar.gz", githubArchive(t))
+
+	out := &MemFS{}
+	g := newTestGenerator(nil, out, "home")
+	g.HeroiconsVersion = "2.2.0"
+	g.HeroiconsMirror = s.URL
+	g.HTTPClient = s.Client()
+	g.Offline = true
+	err := g.Generate()
+	if !errors.Is(err, ErrSourceUnavailable) || !strings.Contains(err.Error(), "offline mode is set") {
+		t.Errorf("got %v, want ErrSourceUnavailable for offline mode", err)
+	}
+	if s.requests.Load() != 0 {
+		t.Errorf("offline mode made %d requests", s.requests.Load())
+	}
+}
+
+func TestGenerateFromNPMPackage(t *testing.T) {
+	archive := testArchive(t, map[string]string{
+		"package/package.json":          `{"version": "2.2.0"}`,
+		"package/LICENSE":               "MIT",
+		"package/index.esm.js":          "not extracted",
+		"package/24/outline/home.svg":   string(testSVG("home").Data),
+		"package/24/outline/index.js":   "not extracted",
+		"package/20/solid/home.svg":     string(testSVG("mini home").Data),
+		"package/16/solid/home.svg":     string(testSVG("micro home").Data),
+		"package/24/solid/esm/home.js":  "not extracted",
+		"package/24/outline/home.d.ts":  "not extracted",
+		"package/optimized/24/home.svg": "not extracted",
+	})
+	s := newReleaseServer(t, "/heroicons/-/heroicons-2.2.0.tgz", archive)
+
+	out := &MemFS{}
+	g := newTestGenerator(nil, out, "home")
+	g.Icons = append(g.Icons, IconSet{Name: "home", Type: IconMicro})
+	g.HeroiconsVersion = "2.2.0"
+	g.HeroiconsNPM = true
+	g.HeroiconsMirror = s.URL
+	g.HTTPClient = s.Client()
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(readOutput(t, out, "icons/micro_home.svg"), "<title>micro home</title>") {
+		t.Error("the downloaded micro icon was not copied")
+	}
+
+	dir, _, err := s.fetch(&Generator{HeroiconsNPM: true}, "2.2.0", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(dir) != "npm-2.2.0" {
+		t.Errorf("npm package cached as %s", dir)
+	}
+	for _, name := range []string{"index.esm.js", "24/outline/index.js", "24/solid/esm", "24/outline/home.d.ts", "optimized"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			t.Errorf("%s was extracted", name)
+		}
+	}
+
+	// A local copy of the package, such as node_modules/heroicons, is detected
+	out = &MemFS{}
+	g = newTestGenerator(nil, out, "home")
+	g.HeroiconsPath = dir
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	readOutput(t, out, "icons/outline_home.svg")
+}
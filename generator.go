@@ -1,11 +1,20 @@
 package heroicons
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
 )
 
@@ -17,6 +26,1058 @@ const (
 // DefaultMissingIconSVG is the default SVG content for the missing icon
 var DefaultMissingIconSVG = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24" fill="#fb2c36"><path d="M17.5 2.5L23 12L17.5 21.5H6.5L1 12L6.5 2.5H17.5ZM11 15V17H13V15H11ZM11 7V13H13V7H11Z"></path></svg>`
 
+// Generator handles the icon generation process. A Generator must not be used by multiple
+// goroutines at once; use GenerateAll to run several generators concurrently.
+type Generator struct {
+	// HeroiconsPath is the path to the heroicons repository, or to the heroicons npm
+	// package, such as node_modules/heroicons
+	HeroiconsPath string
+	// SourceFS, when set, is used instead of HeroiconsPath as the heroicons repository,
+	// such as an embedded copy, a zip archive or a test fixture. The optimized directory
+	// must be at its root.
+	SourceFS fs.FS
+	// Source, when set, is used instead of HeroiconsPath and HeroiconsVersion to read
+	// icons, for example LucideSource, FeatherSource or TablerSource.
+	Source IconSource
+	// HeroiconsVersion, when set, downloads the given heroicons release (such as "2.2.0")
+	// from GitHub into the user cache directory and uses it instead of HeroiconsPath.
+	HeroiconsVersion string
+	// HeroiconsChecksum is the expected SHA-256 checksum (hex) of the release archive
+	// downloaded for HeroiconsVersion. A download that does not match is rejected.
+	HeroiconsChecksum string
+	// HeroiconsNPM if true, HeroiconsVersion is downloaded as the package tarball from the
+	// npm registry instead of the release archive from GitHub, so Go and JavaScript code
+	// can share one icon source
+	HeroiconsNPM bool
+	// Offline if true, HeroiconsVersion is only read from the download cache: generation
+	// fails with ErrSourceUnavailable instead of downloading a release that is not cached,
+	// for CI machines without network access
+	Offline bool
+	// HeroiconsMirror is the base URL of a mirror HeroiconsVersion is downloaded from
+	// instead of https://github.com, or https://registry.npmjs.org with HeroiconsNPM, such
+	// as an Artifactory remote repository. Archive paths below the base URL are unchanged.
+	HeroiconsMirror string
+	// HeroiconsProxy is the URL of the HTTP(S) proxy HeroiconsVersion is downloaded
+	// through. By default, the proxy set in the HTTPS_PROXY environment variable is used.
+	HeroiconsProxy string
+	// HTTPClient, when set, is used to download HeroiconsVersion instead of a client built
+	// from HeroiconsProxy, for custom transports, certificates or authentication
+	HTTPClient *http.Client
+	// OutputPath is where the generated files will be written
+	OutputPath string
+	// Output, when set, is written to instead of the OutputPath directory, such as a
+	// MemFS for tests or build systems that cannot write to disk. OutputPath is still used
+	// to derive the import path of generated subpackages and to report file paths.
+	Output WriteFS
+	// PackageName is the name of the generated package. Defaults to "icons".
+	PackageName string
+	// IconsDir is the name of the directory in OutputPath the icons are copied into.
+	// Defaults to "icons".
+	IconsDir string
+	// ProviderFile is the name of the generated provider file. Defaults to "provider.go".
+	ProviderFile string
+	// Icons is the list of icons to include. A name may be a wildcard pattern, such as
+	// "arrow-*", or "*" for every icon of the type.
+	Icons []IconSet
+	// Exclude is a list of icons, or wildcard patterns, left out of the icons to include,
+	// including those found in AnnotationDirs and TemplateDirs
+	Exclude []IconSet
+	// FailOnError if true, missing icons will cause an error; otherwise, the missing icon will be used
+	FailOnError bool
+	// Deprecated maps the keys (type/name) of deprecated icons to the keys of their
+	// replacements, or to an empty string if there is none. Rendering a deprecated icon
+	// logs a warning once, or fails when StrictDeprecations is set.
+	Deprecated map[string]string
+	// StrictDeprecations if true, the generated package returns an error when a
+	// deprecated icon is rendered. It sets the default of the package's
+	// StrictDeprecations variable.
+	StrictDeprecations bool
+	// MissingIconSVG is the SVG content to use for missing icons. This overrides the default.
+	MissingIconSVG string
+	// ClearIcons if true, the copied icons directory will be cleared before generating new icons.
+	// Note this does not affect the custom icons directory.
+	ClearIcons bool
+	// ValidateIcons if true, every copied icon (and the missing icon) must be well-formed
+	// XML without script elements, event handlers, javascript: URLs or entity
+	// declarations. Generation fails listing the icons that are not.
+	ValidateIcons bool
+	// Sync if true, each generated file is flushed to stable storage after it is written, for output
+	// directories on network file systems or container volumes that may lose buffered
+	// writes. This makes generation slower.
+	Sync bool
+	// Log receives the generator's messages, such as missing icons and skipped
+	// identifiers, and the output of the PostGenerate command. Defaults to os.Stdout.
+	Log io.Writer
+	// OnProgress, if set, is called after each icon is copied (or found missing), with the
+	// number of icons processed so far and the total, so tools can report progress. Calls
+	// are serialized but may come from different goroutines.
+	OnProgress func(done, total int, current IconSet)
+	// Workers is the number of icons copied concurrently. Defaults to GOMAXPROCS.
+	Workers int
+	// WriteLockfile if true, a heroicons.lock file recording the source version and a
+	// checksum of each copied icon is written to the output directory.
+	WriteLockfile bool
+	// UpdateLockfile if true, an existing lockfile is replaced even if the source version
+	// or the content of a locked icon changed. Otherwise, with WriteLockfile, generation
+	// fails with ErrLockfileMismatch before writing any file.
+	UpdateLockfile bool
+	// FailOnMissing if true, generation returns an error wrapping ErrMissingIcons when
+	// icons could not be found. The output is still written, with the missing icons left
+	// out. Unlike FailOnError, this fails the build rather than the render.
+	FailOnMissing bool
+	// Header is a comment, such as a license or attribution notice, written at the top of
+	// every generated Go file. Lines are prefixed with // unless they already are comments.
+	Header string
+	// Attribution if true, an ATTRIBUTION file listing the source library, its version
+	// and license, and the icons copied from it is written to the output directory.
+	Attribution bool
+	// WriteManifest if true, a manifest.json file listing the name, type, file and size of
+	// each icon and the source version is written to the output directory.
+	WriteManifest bool
+	// Merge if true, icons already present in the output directory are kept and included
+	// in the manifest. Configured icons that conflict with an existing icon of the same
+	// type and name are not overwritten; a warning is logged instead. This allows the
+	// generator to be adopted gradually in an existing icons package.
+	Merge bool
+	// AliasRenames if true, icons detected as renamed since the last generation (same
+	// content, different key) keep their old key as an alias in the manifest, so
+	// templates using the old name keep working. Aliases are recorded in the lockfile,
+	// which is written even without WriteLockfile, and carried forward on later
+	// generations.
+	AliasRenames bool
+	// Normalize if true, the root element of copied icons is rewritten so icons are sized
+	// and colored by CSS alone: fixed width and height attributes are removed, outline
+	// icons get stroke="currentColor" and solid icons fill="currentColor".
+	Normalize bool
+	// StripAttributes lists attributes (such as xmlns or xmlns:xlink) to remove from the
+	// copied icons and the missing icon, for sanitizer policies that reject them.
+	StripAttributes []string
+	// GenerateCSPTest if true, a csp_test.go file is written to the output directory that
+	// fails when any embedded icon references an external resource.
+	GenerateCSPTest bool
+	// GenerateTests if true, a provider_test.go file is written next to the provider that
+	// checks every icon in the manifest resolves from the embedded files, the missing icon
+	// renders and FailOnError behaves as configured, so broken embeds fail go test.
+	GenerateTests bool
+	// AnnotationDirs lists directories of Go code to scan for //heroicons:use comments.
+	// The icons they declare are added to Icons.
+	AnnotationDirs []string
+	// TemplateDirs lists directories of templates to scan for icon calls, such as
+	// {{ icon "home" "outline" }}. The icons found are added to Icons.
+	TemplateDirs []string
+	// TemplateFuncs lists the template function names to look for in TemplateDirs.
+	// Defaults to DefaultTemplateFuncs.
+	TemplateFuncs []string
+	// CustomDirs lists directories of SVG icons that are not part of Heroicons to include
+	// in the manifest. An icon name that collides with another icon is an error.
+	CustomDirs []CustomSource
+	// InlineSVG if true, the provider holds the SVG content of the icons in a map instead
+	// of embedding the icon files with //go:embed, so it is a single self-contained file
+	// that never reads a file system. This suits small icon sets.
+	InlineSVG bool
+	// Bundle, when set to BundleZip or BundleTar, writes the icons to bundle.zip or
+	// bundle.tar in the output directory, and the provider reads them from that bundle at
+	// runtime instead of embedding them. Ship the bundle next to the binary; icons can then
+	// be updated by replacing it, without recompiling.
+	Bundle BundleFormat
+	// Sprite if true, a sprite.svg sheet with a <symbol> per icon is written to the output
+	// directory, and the provider gets SpriteSheet and SpriteIcon functions that reference
+	// the symbols instead of inlining the SVG.
+	Sprite bool
+	// CSS if true, an icons.css stylesheet is written to the output directory with a
+	// mask-image utility class per icon (such as hi-outline-home), so icons can be placed
+	// from markup classes alone.
+	CSS bool
+	// DryRun if true, generation resolves the icons and reports the files it would create,
+	// overwrite or remove without writing anything. GenerateIcons only logs the plan. A
+	// release set with HeroiconsVersion is still downloaded to the cache, as it is needed to resolve the icons.
+	DryRun bool
+	// PostGenerate is a command (program and arguments) run after a successful
+	// generation, with the report as JSON on its standard input.
+	PostGenerate []string
+	// Webhook is a URL the report is POSTed to as JSON after a successful generation.
+	Webhook string
+	// DebugHandler if true, the provider gets a DebugHandler function serving icon counts,
+	// memory footprint, render counts and recent missing icons as JSON. It is off by
+	// default because it links net/http and encoding/json into every program using the
+	// package.
+	DebugHandler bool
+	// PickerHandler if true, the provider gets a PickerHandler function serving the icons
+	// as paginated JSON for an icon picker (see iconhttp.PickerHandler)
+	PickerHandler bool
+	// ProfileLabels if true, the provider gets a ProfileLabels variable that, when set,
+	// labels icon lookups with pprof labels for CPU profiles
+	ProfileLabels bool
+	// Accessors if true, the provider gets a function per icon name, such as
+	// Home(iconType, class), so editors can autocomplete the available icons.
+	Accessors bool
+	// SplitTypes if true, a separate package is generated for each icon type, in a
+	// subdirectory of OutputPath named after the type (outline, solid, mini, micro and
+	// custom). Each package embeds only its own icons, so a binary importing only the
+	// outline package does not embed the other types. PackageName is ignored.
+	SplitTypes bool
+	// BuildTags if true, the icons of each type (outline, solid, mini and micro) are
+	// registered in a separate file guarded by a build tag, so builds can exclude a whole
+	// type with -tags, such as -tags heroicons_no_micro (see BuildTag).
+	BuildTags bool
+	// LinkedFuncs if true, a linked.go file is generated with a function per icon, such
+	// as OutlineHome(class), that holds the icon's content. Icons whose function is never
+	// called are removed by the linker, as long as no lookup by name (such as RenderIcon)
+	// is used.
+	LinkedFuncs bool
+	// TypedFuncs if true, a subpackage is generated for each icon type (outline, solid,
+	// mini and micro) with a render function per icon, such as outline.ArrowRight(class).
+	TypedFuncs bool
+	// ImportPath is the import path of the generated package, used by the typed
+	// subpackages. If empty, it is derived from the go.mod enclosing OutputPath.
+	ImportPath string
+
+	// configPath is the config file loaded with LoadConfig, if any
+	configPath string
+	// downloadPath is the cache directory of the downloaded release, if any
+	downloadPath string
+	// archiveChecksum is the checksum of the downloaded release archive, if any
+	archiveChecksum string
+}
+
+// Report summarizes the result of a generation run
+type Report struct {
+	// OutputPath is the directory the icons were generated into
+	OutputPath string `json:"output_path"`
+	// Icons lists the keys (type/name) of the icons included in the provider
+	Icons []string `json:"icons"`
+	// Missing lists the keys (type/name) of the icons that could not be found
+	Missing []string `json:"missing"`
+	// Conflicts lists the keys (type/name) of existing icons that were kept because
+	// they differ from the source icon (see Generator.Merge)
+	Conflicts []string `json:"conflicts"`
+	// Renames maps the keys of icons from the previous generation to the keys of new
+	// icons with identical content
+	Renames map[string]string `json:"renames"`
+	// Aliases maps the old keys kept as aliases to the keys of the icons they render,
+	// including aliases carried forward from earlier generations (see AliasRenames)
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// Created lists the files a dry run would create (see Generator.DryRun)
+	Created []string `json:"created,omitempty"`
+	// Overwritten lists the existing files a dry run would overwrite
+	Overwritten []string `json:"overwritten,omitempty"`
+	// Removed lists the existing icon files a dry run would remove (see ClearIcons)
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Generate creates the icon manifest and copies the required icons
+func (g *Generator) Generate() error {
+	return g.GenerateContext(context.Background())
+}
+
+// GenerateContext is like Generate, but stops when ctx is canceled. Cancellation is
+// checked while downloading a release and between icons; the context's error is returned.
+func (g *Generator) GenerateContext(ctx context.Context) error {
+	_, err := g.GenerateReportContext(ctx)
+	return err
+}
+
+// GenerateReport creates the icon manifest and copies the required icons, returning
+// a report of the icons that were included and those that were missing
+func (g *Generator) GenerateReport() (*Report, error) {
+	return g.GenerateReportContext(context.Background())
+}
+
+// GenerateReportContext is like GenerateReport, but stops when ctx is canceled. The
+// provider is only regenerated once every icon has been copied, so a canceled run leaves
+// the previously generated provider in place.
+func (g *Generator) GenerateReportContext(ctx context.Context) (*Report, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := g.resolveSource(ctx); err != nil {
+		return nil, err
+	}
+
+	if g.SplitTypes {
+		return g.generateSplit(ctx)
+	}
+
+	if g.DryRun {
+		return g.dryRun()
+	}
+
+	icons, err := g.resolveIcons()
+	if err != nil {
+		return nil, err
+	}
+
+	custom, err := g.customIcons(icons)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCaseCollisions(icons, custom); err != nil {
+		return nil, err
+	}
+
+	if g.WriteLockfile && !g.UpdateLockfile {
+		if err := g.checkLockfile(icons, custom); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := g.writeMissingIcon(); err != nil {
+		return nil, err
+	}
+
+	out := g.output()
+	dir := g.iconsDirName()
+
+	// Remember the previous icons so renames can be detected
+	previous := iconChecksums(out, dir)
+	var previousAliases map[string]string
+	if g.AliasRenames {
+		if previousAliases, err = g.previousAliases(); err != nil {
+			return nil, err
+		}
+	}
+
+	if g.ClearIcons {
+		// Clear existing icons
+		if err := out.RemoveAll(dir); err != nil {
+			return nil, fmt.Errorf("failed to clear icons directory: %w", err)
+		}
+	}
+
+	if err := out.MkdirAll(dir); err != nil {
+		return nil, fmt.Errorf("failed to create icons output directory: %w", err)
+	}
+
+	// Copy icons and build manifest
+	iconPaths := make(map[string]string)
+
+	var conflicts []string
+	if g.Merge {
+		existing, err := existingIconPaths(out, dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read icons directory: %w", err)
+		}
+		iconPaths = existing
+		icons, conflicts = g.mergeExisting(icons, iconPaths)
+	}
+
+	progress := g.newProgress(len(icons) + len(custom))
+	missingIcons, err := g.copyIcons(ctx, icons, iconPaths, progress)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.copyCustomIcons(custom, iconPaths, progress); err != nil {
+		return nil, err
+	}
+
+	if g.Attribution {
+		if err := g.writeAttribution(iconPaths, custom); err != nil {
+			return nil, fmt.Errorf("failed to write attribution: %w", err)
+		}
+	}
+
+	renames := detectRenames(previous, out, dir, iconPaths)
+	var aliases map[string]string
+	if g.AliasRenames {
+		aliases = carryAliases(previousAliases, renames, iconPaths)
+		for oldKey, target := range aliases {
+			iconPaths[oldKey] = iconPaths[target]
+		}
+	}
+
+	if g.WriteLockfile || g.AliasRenames {
+		if err := g.writeLockfile(iconPaths, aliases); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := g.writeOutputs(iconPaths); err != nil {
+		return nil, err
+	}
+
+	g.logMissingIcons(missingIcons)
+
+	if len(conflicts) > 0 {
+		g.logf("The following icons already exist with different content and were kept:\n%s\n",
+			strings.Join(conflicts, "\n"))
+	}
+
+	g.logRenames(renames)
+
+	report := &Report{
+		OutputPath: g.OutputPath,
+		Icons:      make([]string, 0, len(iconPaths)),
+		Missing:    missingIcons,
+		Conflicts:  conflicts,
+		Renames:    renames,
+		Aliases:    aliases,
+	}
+	for key := range iconPaths {
+		report.Icons = append(report.Icons, key)
+	}
+	sort.Strings(report.Icons)
+
+	if err := g.runHooks(report); err != nil {
+		return report, err
+	}
+
+	return report, g.missingError(missingIcons)
+}
+
+// GenerateIcons copies only the given icons into an existing output directory and
+// regenerates the provider with them added to the manifest. Icons already present in
+// the output directory are kept, so adding a single icon during development does not
+// require copying the whole set again. With SplitTypes, each icon is added to the
+// package of its type.
+func (g *Generator) GenerateIcons(icons ...IconSet) error {
+	if err := g.Validate(); err != nil {
+		return err
+	}
+
+	if err := g.resolveSource(context.Background()); err != nil {
+		return err
+	}
+	if err := errors.Join(validateIcons(icons)...); err != nil {
+		return fmt.Errorf("invalid icons: %w", err)
+	}
+	if g.SplitTypes {
+		return g.generateSplitIcons(icons)
+	}
+	if g.DryRun {
+		return g.dryRunIcons(icons)
+	}
+	if g.WriteLockfile && !g.UpdateLockfile {
+		if err := g.checkLockfile(icons, nil); err != nil {
+			return err
+		}
+	}
+
+	if err := g.writeMissingIcon(); err != nil {
+		return err
+	}
+
+	out := g.output()
+	dir := g.iconsDirName()
+	if err := out.MkdirAll(dir); err != nil {
+		return fmt.Errorf("failed to create icons output directory: %w", err)
+	}
+
+	iconPaths, err := existingIconPaths(out, dir)
+	if err != nil {
+		return fmt.Errorf("failed to read icons directory: %w", err)
+	}
+
+	missingIcons, err := g.copyIcons(context.Background(), icons, iconPaths, g.newProgress(len(icons)))
+	if err != nil {
+		return err
+	}
+
+	var aliases map[string]string
+	if g.AliasRenames {
+		previousAliases, err := g.previousAliases()
+		if err != nil {
+			return err
+		}
+		aliases = carryAliases(previousAliases, nil, iconPaths)
+		for oldKey, target := range aliases {
+			iconPaths[oldKey] = iconPaths[target]
+		}
+	}
+
+	if g.WriteLockfile || g.AliasRenames {
+		if err := g.writeLockfile(iconPaths, aliases); err != nil {
+			return err
+		}
+	}
+
+	if g.Attribution {
+		custom, err := g.customIcons(nil)
+		if err != nil {
+			return err
+		}
+		if err := g.writeAttribution(iconPaths, custom); err != nil {
+			return fmt.Errorf("failed to write attribution: %w", err)
+		}
+	}
+
+	if err := g.writeOutputs(iconPaths); err != nil {
+		return err
+	}
+
+	g.logMissingIcons(missingIcons)
+
+	return g.missingError(missingIcons)
+}
+
+// GenerateAll runs the given generators concurrently and returns their reports in the
+// same order. Each generator must write to a distinct OutputPath.
+func GenerateAll(generators ...*Generator) ([]*Report, error) {
+	seen := make(map[string]bool, len(generators))
+	for _, g := range generators {
+		out := filepath.Clean(g.OutputPath)
+		if seen[out] {
+			return nil, fmt.Errorf("multiple generators write to %s", out)
+		}
+		seen[out] = true
+	}
+
+	reports := make([]*Report, len(generators))
+	errs := make([]error, len(generators))
+
+	var wg sync.WaitGroup
+	for i, g := range generators {
+		wg.Add(1)
+		go func(i int, g *Generator) {
+			defer wg.Done()
+			report, err := g.GenerateReport()
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", g.OutputPath, err)
+				return
+			}
+			reports[i] = report
+		}(i, g)
+	}
+	wg.Wait()
+
+	return reports, errors.Join(errs...)
+}
+
+// writeOutputs generates the provider and the other configured outputs for the icons in
+// the manifest
+func (g *Generator) writeOutputs(iconPaths map[string]string) error {
+	var spriteViewBoxes map[string]string
+	if g.Sprite {
+		var err error
+		if spriteViewBoxes, err = g.writeSprite(iconPaths); err != nil {
+			return fmt.Errorf("failed to write sprite sheet: %w", err)
+		}
+	}
+
+	if err := g.writeBundle(iconPaths); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	// Generate provider.go
+	if err := g.generateProvider(iconPaths, spriteViewBoxes); err != nil {
+		return fmt.Errorf("failed to generate provider: %w", err)
+	}
+
+	if g.GenerateCSPTest {
+		if err := g.generateCSPTest(); err != nil {
+			return fmt.Errorf("failed to write CSP test: %w", err)
+		}
+	}
+
+	if g.GenerateTests {
+		if err := g.generateProviderTest(); err != nil {
+			return fmt.Errorf("failed to write provider test: %w", err)
+		}
+	}
+
+	if g.CSS {
+		if err := g.writeCSS(iconPaths); err != nil {
+			return fmt.Errorf("failed to write stylesheet: %w", err)
+		}
+	}
+
+	if g.WriteManifest {
+		if err := g.writeManifest(iconPaths); err != nil {
+			return fmt.Errorf("failed to write manifest: %w", err)
+		}
+	}
+
+	if g.LinkedFuncs {
+		if err := g.generateLinkedFuncs(iconPaths); err != nil {
+			return fmt.Errorf("failed to generate linked functions: %w", err)
+		}
+	} else if err := g.removeLinkedFuncs(); err != nil {
+		return err
+	}
+
+	if g.TypedFuncs {
+		if err := g.generateTypedFuncs(iconPaths); err != nil {
+			return fmt.Errorf("failed to generate typed functions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeMissingIcon creates the custom icons directory and writes the missing icon SVG
+func (g *Generator) writeMissingIcon() error {
+	if g.MissingIconSVG == "" {
+		g.MissingIconSVG = DefaultMissingIconSVG
+	}
+
+	if err := g.output().MkdirAll(customIconsDir); err != nil {
+		return fmt.Errorf("failed to create custom output directory: %w", err)
+	}
+
+	if err := g.writeIcon([]byte(g.MissingIconSVG), customIconsDir+"/missing.svg", IconCustom); err != nil {
+		return fmt.Errorf("failed to write missing icon: %w", err)
+	}
+
+	return nil
+}
+
+// copyIcons copies the given icons into the icons directory, adding each copied icon to
+// iconPaths. It returns the keys of the icons that could not be found.
+func (g *Generator) copyIcons(ctx context.Context, icons []IconSet, iconPaths map[string]string, progress *progress) ([]string, error) {
+	// Each worker records whether its icons were copied by index, so the manifest and the
+	// missing icons are built in the order of icons whatever the scheduling
+	copied := make([]bool, len(icons))
+	errs := make([]error, len(icons))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(g.workers(), len(icons)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				icon := icons[i]
+				destPath := g.iconsDirName() + "/" + fmt.Sprintf("%s_%s.svg", icon.Type, icon.Name)
+
+				if content, err := g.readIcon(icon); err == nil {
+					if err := g.writeIcon(content, destPath, icon.Type); err != nil {
+						errs[i] = fmt.Errorf("%s: %w", icon.Key(), err)
+					} else {
+						copied[i] = true
+					}
+				}
+				progress.step(icon)
+			}
+		}()
+	}
+	for i := range icons {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("failed to copy icons: %w", err)
+	}
+
+	var missingIcons []string
+	for i, icon := range icons {
+		key := fmt.Sprintf("%s/%s", icon.Type, icon.Name)
+		if !copied[i] {
+			missingIcons = append(missingIcons, key)
+			continue
+		}
+		iconPaths[key] = fmt.Sprintf("%s_%s.svg", icon.Type, icon.Name)
+	}
+	return missingIcons, nil
+}
+
+// workers returns the number of icons copied concurrently
+func (g *Generator) workers() int {
+	if g.Workers > 0 {
+		return g.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// mergeExisting removes the icons that already exist in iconPaths from icons, returning
+// the icons that still need to be copied and the keys of existing icons whose content
+// differs from the source
+func (g *Generator) mergeExisting(icons []IconSet, iconPaths map[string]string) ([]IconSet, []string) {
+	var toCopy []IconSet
+	var conflicts []string
+	for _, icon := range icons {
+		key := fmt.Sprintf("%s/%s", icon.Type, icon.Name)
+		filename, ok := iconPaths[key]
+		if !ok {
+			toCopy = append(toCopy, icon)
+			continue
+		}
+
+		src, err := g.readIcon(icon)
+		if err != nil {
+			// Keep the existing icon when the source is unavailable
+			continue
+		}
+
+		existing, err := fs.ReadFile(g.output(), g.iconsDirName()+"/"+filename)
+		if err != nil || !bytes.Equal(g.processIcon(src, icon.Type), existing) {
+			conflicts = append(conflicts, key)
+		}
+	}
+	return toCopy, conflicts
+}
+
+// existingIconPaths builds the manifest for the icons already copied into the directory
+// dir of fsys
+func existingIconPaths(fsys fs.FS, dir string) (map[string]string, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	iconPaths := make(map[string]string)
+	for _, entry := range entries {
+		filename := entry.Name()
+		if entry.IsDir() || path.Ext(filename) != ".svg" {
+			continue
+		}
+
+		iconType, name, ok := strings.Cut(strings.TrimSuffix(filename, ".svg"), "_")
+		if !ok {
+			continue
+		}
+		iconPaths[fmt.Sprintf("%s/%s", iconType, name)] = filename
+	}
+	return iconPaths, nil
+}
+
+// logWriter returns Log, or os.Stdout if it is not set
+func (g *Generator) logWriter() io.Writer {
+	if g.Log == nil {
+		return os.Stdout
+	}
+	return g.Log
+}
+
+// logf writes a message to Log
+func (g *Generator) logf(format string, args ...any) {
+	_, _ = fmt.Fprintf(g.logWriter(), format, args...)
+}
+
+// logRenames logs which icons appear to have been renamed
+func (g *Generator) logRenames(renames map[string]string) {
+	oldKeys := make([]string, 0, len(renames))
+	for oldKey := range renames {
+		oldKeys = append(oldKeys, oldKey)
+	}
+	sort.Strings(oldKeys)
+
+	for _, oldKey := range oldKeys {
+		g.logf("Icon %s appears to have been renamed to %s\n", oldKey, renames[oldKey])
+	}
+}
+
+// logMissingIcons logs which icons are missing
+func (g *Generator) logMissingIcons(missingIcons []string) {
+	if len(missingIcons) > 0 {
+		g.logf("The following icons were not found and could not be copied:\n%s\n",
+			strings.Join(missingIcons, "\n"))
+	}
+}
+
+func (g *Generator) copyIcon(src, dest string, iconType IconType) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return g.writeIcon(content, dest, iconType)
+}
+
+// writeIcon processes the SVG content and writes it to the output file dest
+func (g *Generator) writeIcon(content []byte, dest string, iconType IconType) error {
+	content = g.processIcon(content, iconType)
+	if g.ValidateIcons {
+		if err := checkSVG(content); err != nil {
+			return fmt.Errorf("invalid SVG: %w", err)
+		}
+	}
+	return g.output().WriteFile(dest, content)
+}
+
+const providerTemplate = `// Code generated by heroicons generator; DO NOT EDIT.
+
+// Package {{.PackageName}} provides the icons embedded by the heroicons generator.
+//
+// All functions in this package are safe for concurrent use. Set the configuration variables
+// (FailOnError, ValidateOutput,{{ if .ProfileLabels }} ProfileLabels,{{ end }} StrictDeprecations, DebugSource,
+// AuditARIA and RecoverPanics) during startup, before any icon is rendered. To change them later, for example
+// from tests running in parallel with rendering, use their Set functions instead.
+package {{.PackageName}}
+
+import (
+{{- if or .Bundle .ProfileLabels }}
+	"context"
+{{- end }}
+{{- if .Bundle }}
+{{- if .Sprite }}
+	_ "embed"
+{{- end }}
+{{- else if not .InlineSVG }}
+	"embed"
+{{- end }}
+{{- if .DebugHandler }}
+	"encoding/json"
+{{- end }}
+	"fmt"
+	"html/template"
+	"io"
+{{- if not .Bundle }}
+	"io/fs"
+{{- end }}
+	"log"
+{{- if or .DebugHandler .PickerHandler }}
+	"net/http"
+{{- end }}
+{{- if .Bundle }}
+	"os"
+	"os/signal"
+	"path/filepath"
+{{- end }}
+{{- if .ProfileLabels }}
+	"runtime/pprof"
+{{- end }}
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+{{- if .Bundle }}
+	"syscall"
+{{- end }}
+{{- if .DebugHandler }}
+	"time"
+{{- end }}
+
+	"github.com/patrickward/go-heroicons/core"
+{{- if .Bundle }}
+	"github.com/patrickward/go-heroicons/iconbundle"
+{{- end }}
+{{- if .PickerHandler }}
+	"github.com/patrickward/go-heroicons/iconhttp"
+{{- end }}
+)
+
+const IconCustom = "custom"
+
+{{- if .InlineSVG }}
+
+// iconFiles holds the content of the icon files, keyed by path
+var iconFiles = map[string]string{
+{{- range $path, $content := .InlineFiles }}
+	"{{ $path }}": {{ printf "%q" $content }},
+{{- end }}
+}
+
+// readIconFile returns the content of the icon file at path
+func readIconFile(path string) ([]byte, error) {
+	content, ok := iconFiles[path]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	return []byte(content), nil
+}
+
+// iconFilePaths returns the paths of all icon files
+func iconFilePaths() ([]string, error) {
+	paths := make([]string, 0, len(iconFiles))
+	for path := range iconFiles {
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+{{- if .BuildTags }}
+
+// registerIcons adds the icons of a file guarded by a build tag to the manifest
+func registerIcons(paths, files map[string]string) {
+	for key, path := range paths {
+		iconPaths[key] = path
+	}
+	for path, content := range files {
+		iconFiles[path] = content
+	}
+}
+{{- end }}
+{{- else if .Bundle }}
+
+// BundlePath is the path of the icon bundle, loaded when the first icon is read unless
+// LoadBundle is called first. A relative path is looked up in the directory of the
+// executable, then in the working directory, which is the package directory in tests.
+var BundlePath = "{{.BundleFile}}"
+
+var (
+	bundle atomic.Pointer[iconbundle.Bundle]
+	// bundleMu serializes loading the bundle and guards bundleFile
+	bundleMu sync.Mutex
+	// bundleFile is the path the current bundle was loaded from
+	bundleFile string
+	// bundleWarned is set once a failure to load the bundle has been logged, as icons
+	// fall back to the missing icon, or render empty, without it
+	bundleWarned atomic.Bool
+)
+
+// LoadBundle opens the icon bundle at path and reads icons from it from then on. The
+// bundle is swapped in atomically: icons being rendered finish with the previous bundle,
+// and if the new bundle cannot be read the previous one is kept. Icons are looked up in
+// the index of the bundle, so it may add, rename and remove icons; the name constants and
+// accessors compiled into this package are not changed.
+func LoadBundle(path string) error {
+	bundleMu.Lock()
+	defer bundleMu.Unlock()
+	return loadBundle(path)
+}
+
+// Reload reads the bundle again from the path it was loaded from, or BundlePath if none
+// was loaded yet, and swaps it in like LoadBundle, so icon fixes can be rolled out by
+// replacing the bundle file without restarting
+func Reload() error {
+	bundleMu.Lock()
+	defer bundleMu.Unlock()
+
+	path := bundleFile
+	if path == "" {
+		path = defaultBundlePath()
+	}
+	return loadBundle(path)
+}
+
+// ReloadOnSignal calls Reload whenever the process receives one of the given signals,
+// SIGHUP if none are given, until ctx is done. Failures are logged and keep the current
+// bundle.
+func ReloadOnSignal(ctx context.Context, signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ch:
+				if err := Reload(); err != nil {
+					log.Printf("heroicons: failed to reload icon bundle: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// loadBundle opens the bundle at path and swaps it in. bundleMu must be held.
+func loadBundle(path string) error {
+	b, err := iconbundle.Open(path)
+	if err != nil {
+		return err
+	}
+	bundle.Store(b)
+	bundleFile = path
+	return nil
+}
+
+// loadedBundle returns the icon bundle, loading it from BundlePath on first use
+func loadedBundle() (*iconbundle.Bundle, error) {
+	if b := bundle.Load(); b != nil {
+		return b, nil
+	}
+
+	bundleMu.Lock()
+	defer bundleMu.Unlock()
+	if b := bundle.Load(); b != nil {
+		return b, nil
+	}
+
+	if err := loadBundle(defaultBundlePath()); err != nil {
+		if !bundleWarned.Swap(true) {
+			log.Printf("heroicons: failed to load icon bundle: %v", err)
+		}
+		return nil, err
+	}
+	return bundle.Load(), nil
+}
+
+// defaultBundlePath resolves BundlePath, looking for a relative path in the directory of
+// the executable, then in the working directory
+func defaultBundlePath() string {
+	path := BundlePath
+	if !filepath.IsAbs(path) {
+		if exe, err := os.Executable(); err == nil {
+			candidate := filepath.Join(filepath.Dir(exe), path)
+			if _, err := os.Stat(candidate); err == nil {
+				return candidate
+			}
+		}
+	}
+	return path
+}
+
+// readIconFile returns the content of the icon file at path
+func readIconFile(path string) ([]byte, error) {
+	b, err := loadedBundle()
+	if err != nil {
+		return nil, err
+	}
+	return b.ReadFile(path)
+}
+
+// iconFilePaths returns the paths of all icon files
+func iconFilePaths() ([]string, error) {
+	b, err := loadedBundle()
+	if err != nil {
+		return nil, err
+	}
+	return b.Files(), nil
+}
+{{- else }}
+
+//go:embed {{.EmbedPatterns}}
+var iconFS embed.FS
+{{- if .BuildTags }}
+
+// typeFS holds the icons of the files guarded by build tags
+var typeFS []embed.FS
+
+// registerIcons adds the icons of a file guarded by a build tag to the manifest
+func registerIcons(paths map[string]string, fsys embed.FS) {
+	for key, path := range paths {
+		iconPaths[key] = path
+	}
+	typeFS = append(typeFS, fsys)
+}
+{{- end }}
+
+// readIconFile returns the content of the icon file at path
+func readIconFile(path string) ([]byte, error) {
+	content, err := iconFS.ReadFile(path)
+{{- if .BuildTags }}
+	for _, fsys := range typeFS {
+		if err == nil {
+			break
+		}
+		content, err = fsys.ReadFile(path)
+	}
+{{- end }}
+	return content, err
+}
+
+// iconFilePaths returns the paths of all icon files
+func iconFilePaths() ([]string, error) {
+	var paths []string
+	for _, fsys := range {{ if .BuildTags }}append([]embed.FS{iconFS}, typeFS...){{ else }}[]embed.FS{iconFS}{{ end }} {
+		err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			paths = append(paths, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+{{- end }}
+
+// FailOnError determines whether to use a generic missing icon when an icon is not found.
+// It defaults to {{ .FailOnError }}.
+var FailOnError = {{ .FailOnError }}
+
 // IconType represents the different types of Heroicons
 type IconType string
 
@@ -25,274 +1086,702 @@ const (
 	IconSolid   IconType = "solid"   // 24px solid icons
 	IconMini    IconType = "mini"    // 20px solid icons
 	IconMicro   IconType = "micro"   // 16px solid icons
-	IconCustom  IconType = "custom"  // Custom icons (not part of Heroicons)
 )
 
-// IconSet defines an icon to be included in the project
-type IconSet struct {
-	Name string
-	Type IconType
+// ValidateOutput, when true, validates every rendered icon with core.ValidateSVG and
+// returns an error for invalid markup. It is intended for debug builds and tests.
+var ValidateOutput = false
+{{- if .ProfileLabels }}
+
+// ProfileLabels, when true, labels icon lookups with the icon name and type (pprof labels
+// "icon" and "icon_type"), so CPU profiles show which icons are expensive to render.
+var ProfileLabels = false
+{{- end }}
+
+// StrictDeprecations, when true, makes rendering a deprecated icon an error instead of
+// logging a warning. It defaults to {{ .StrictDeprecations }}.
+var StrictDeprecations = {{ .StrictDeprecations }}
+
+// DebugSource, when true, annotates every rendered icon with a data-hi-src attribute
+// naming the file and line that rendered it, to find where a stray icon comes from. It
+// is intended for development only.
+var DebugSource = false
+
+// AuditARIA, when set, is called whenever an icon is rendered without aria-hidden or an
+// accessible name. Set it to a function that logs during development, or panics in tests,
+// to find icons that are not accessible.
+var AuditARIA func(name string, iconType core.IconType)
+
+// RecoverPanics, when true, turns a panic while rendering an icon, such as in a Renderer
+// passed to RenderWith or in AuditARIA, into an error wrapping core.ErrPanic, and Icon
+// renders the missing icon instead. Set it in servers so a bug cannot take down the
+// goroutine serving a request; leave it off in tests that rely on AuditARIA panicking.
+var RecoverPanics = false
+
+// The values set with the Set functions, which take precedence over the variables
+var (
+	failOnError        core.Override[bool]
+	validateOutput     core.Override[bool]
+{{- if .ProfileLabels }}
+	profileLabels      core.Override[bool]
+{{- end }}
+	strictDeprecations core.Override[bool]
+	debugSource        core.Override[bool]
+	auditARIA          core.Override[func(name string, iconType core.IconType)]
+	recoverPanics      core.Override[bool]
+)
+
+// SetFailOnError sets FailOnError while icons may be rendered concurrently
+func SetFailOnError(v bool) { failOnError.Set(v) }
+
+// SetValidateOutput sets ValidateOutput while icons may be rendered concurrently
+func SetValidateOutput(v bool) { validateOutput.Set(v) }
+{{- if .ProfileLabels }}
+
+// SetProfileLabels sets ProfileLabels while icons may be rendered concurrently
+func SetProfileLabels(v bool) { profileLabels.Set(v) }
+{{- end }}
+
+// SetStrictDeprecations sets StrictDeprecations while icons may be rendered concurrently
+func SetStrictDeprecations(v bool) { strictDeprecations.Set(v) }
+
+// SetDebugSource sets DebugSource while icons may be rendered concurrently
+func SetDebugSource(v bool) { debugSource.Set(v) }
+
+// SetAuditARIA sets AuditARIA while icons may be rendered concurrently. A nil audit stops
+// auditing.
+func SetAuditARIA(audit func(name string, iconType core.IconType)) { auditARIA.Set(audit) }
+
+// SetRecoverPanics sets RecoverPanics while icons may be rendered concurrently
+func SetRecoverPanics(v bool) { recoverPanics.Set(v) }
+
+{{- if .IconNames }}
+
+// Names of the embedded icons, for use with RenderIcon and Icon
+const (
+{{- range .IconNames }}
+	{{ .Ident }} = "{{ .Name }}"
+{{- end }}
+)
+{{- end }}
+
+{{- if .Accessors }}
+{{- range .Accessors }}
+
+// {{ .Ident }} renders the {{ .Name }} icon of the given type with the given classes
+func {{ .Ident }}(iconType core.IconType, class string) template.HTML {
+	return Icon("{{ .Name }}", iconType, class)
 }
+{{- end }}
+{{- end }}
 
-// Generator handles the icon generation process
-type Generator struct {
-	// HeroiconsPath is the path to the heroicons repository
-	HeroiconsPath string
-	// OutputPath is where the generated files will be written
-	OutputPath string
-	// PackageName is the name of the generated package
-	PackageName string
-	// Icons is the list of icons to include
-	Icons []IconSet
-	// FailOnError if true, missing icons will cause an error; otherwise, the missing icon will be used
-	FailOnError bool
-	// MissingIconSVG is the SVG content to use for missing icons. This overrides the default.
-	MissingIconSVG string
-	// ClearIcons if true, the copied icons directory will be cleared before generating new icons.
-	// Note this does not affect the custom icons directory.
-	ClearIcons bool
+var iconPaths = map[string]string{
+{{- range $key, $path := .IconPaths }}
+	"{{ $key }}": "{{ $path }}",
+{{- end }}
 }
 
-// Generate creates the icon manifest and copies the required icons
-func (g *Generator) Generate() error {
-	if g.MissingIconSVG == "" {
-		g.MissingIconSVG = DefaultMissingIconSVG
+// WriteIcon writes the SVG content for the requested icon with added classes to w. It is
+// the entry point RenderIcon and Icon build on, and lets callers choose per request what
+// happens when the icon is not found.
+func WriteIcon(w io.Writer, req core.Request) (_ int, err error) {
+	if recoverPanics.Get(RecoverPanics) {
+		defer core.CatchPanic(&err)
 	}
-
-	// Create output directories
-	iconsPath := filepath.Join(g.OutputPath, iconsDir)
-	customPath := filepath.Join(g.OutputPath, customIconsDir)
-
-	if err := os.MkdirAll(customPath, 0755); err != nil {
-		return fmt.Errorf("failed to create custom output directory: %w", err)
+	svg, err := renderIcon(req)
+	if err != nil {
+		return 0, err
 	}
+	return io.WriteString(w, svg)
+}
 
-	// Write our missing icon SVG
-	missingIconPath := filepath.Join(customPath, "missing.svg")
-	if err := os.WriteFile(missingIconPath, []byte(g.MissingIconSVG), 0644); err != nil {
-		return fmt.Errorf("failed to write missing icon: %w", err)
+// RenderWith resolves the requested icon like WriteIcon and draws it to w with r, so
+// alternate output formats (PNG, PDF, terminal previews) share the same lookup,
+// deprecation and missing icon handling. Nothing is drawn when the request resolves to no
+// icon, as with core.MissingEmpty.
+func RenderWith(w io.Writer, r core.Renderer, req core.Request) (err error) {
+	if recoverPanics.Get(RecoverPanics) {
+		defer core.CatchPanic(&err)
+	}
+	svg, err := renderIcon(req)
+	if err != nil || svg == "" {
+		return err
 	}
+	return r.Render(w, []byte(svg), req)
+}
 
-	if g.ClearIcons {
-		// Clear existing icons
-		if err := os.RemoveAll(iconsPath); err != nil {
-			return fmt.Errorf("failed to clear icons directory: %w", err)
-		}
+// RenderIcon returns the SVG content for the specified icon with added classes
+func RenderIcon(name string, iconType core.IconType, class string) (_ template.HTML, err error) {
+	if recoverPanics.Get(RecoverPanics) {
+		defer core.CatchPanic(&err)
+	}
+	svg, err := renderIcon(core.Request{Name: name, Type: iconType, Class: class})
+	if err != nil {
+		return "", err
 	}
+	return template.HTML(svg), nil
+}
 
-	if err := os.MkdirAll(iconsPath, 0755); err != nil {
-		return fmt.Errorf("failed to create icons output directory: %w", err)
+// Icon returns the SVG content for the specified icon like RenderIcon, but never returns
+// an error. Failures are logged and the missing icon is rendered instead, so a missing
+// icon does not halt template execution.
+func Icon(name string, iconType core.IconType, class string) template.HTML {
+	svg, err := RenderIcon(name, iconType, class)
+	if err != nil {
+		log.Printf("heroicons: %v", err)
+		svg := addClass(getMissingIcon(), class)
+		if debugSource.Get(DebugSource) {
+			svg = addSource(svg)
+		}
+		return template.HTML(svg)
 	}
+	return svg
+}
 
-	// Copy icons and build manifest
-	var missingIcons []string
-	iconPaths := make(map[string]string)
-	for _, icon := range g.Icons {
-		srcPath := g.getIconPath(icon)
-		filename := fmt.Sprintf("%s_%s.svg", icon.Type, icon.Name)
-		destPath := filepath.Join(iconsPath, filename)
+// renderIcon returns the SVG content for the requested icon with added classes
+func renderIcon(req core.Request) (string, error) {
+	countRender(req.Name, req.Type)
 
-		if err := g.copyIcon(srcPath, destPath); err != nil {
-			missingIcons = append(missingIcons, fmt.Sprintf("%s/%s", icon.Type, icon.Name))
-			continue
-		}
+	if err := checkDeprecated(req.Name, req.Type); err != nil {
+		return "", err
+	}
 
-		key := fmt.Sprintf("%s/%s", icon.Type, icon.Name)
-		iconPaths[key] = filename
+	svg, err := fetchIcon(req.Name, req.Type, req.OnMissing)
+	if err != nil || svg == "" {
+		return "", err
 	}
 
-	// Generate provider.go
-	if err := g.generateProvider(iconPaths); err != nil {
-		return fmt.Errorf("failed to generate provider: %w", err)
+	if audit := auditARIA.Get(AuditARIA); audit != nil && !core.IsAccessible(svg) {
+		audit(req.Name, req.Type)
 	}
 
-	// Log which icons are missing
-	if len(missingIcons) > 0 {
-		fmt.Printf("The following icons were not found and could not be copied:\n%s\n",
-			strings.Join(missingIcons, "\n"))
+	svg = addClass(svg, req.Class)
+	if debugSource.Get(DebugSource) {
+		svg = addSource(svg)
+	}
+	if validateOutput.Get(ValidateOutput) {
+		if err := core.ValidateSVG([]byte(svg)); err != nil {
+			return "", fmt.Errorf("invalid icon %s/%s: %w", req.Type, req.Name, err)
+		}
 	}
 
-	return nil
+	return svg, nil
 }
 
-func (g *Generator) getIconPath(icon IconSet) string {
-	var dir string
-	switch icon.Type {
-	case IconOutline:
-		dir = "24/outline"
-	case IconSolid:
-		dir = "24/solid"
-	case IconMini:
-		dir = "20/solid"
-	case IconMicro:
-		dir = "16/solid"
-	case IconCustom:
-		dir = "custom"
+// addClass inserts the given classes into the SVG. The classes are escaped, as they may
+// come from request parameters (see iconhttp.FragmentHandler).
+func addClass(svg, class string) string {
+	if class == "" {
+		return svg
 	}
-	return filepath.Join(g.HeroiconsPath, "optimized", dir, icon.Name+".svg")
-}
-
-func (g *Generator) copyIcon(src, dest string) error {
-	srcFile, err := os.Open(src)
-	if err != nil {
-		return err
+	class = template.HTMLEscapeString(class)
+	if strings.Contains(svg, "class=\"") {
+		return strings.Replace(svg, "class=\"", fmt.Sprintf("class=\"%s ", class), 1)
 	}
+	return strings.Replace(svg, "<svg ", fmt.Sprintf("<svg class=\"%s\" ", class), 1)
+}
 
-	defer func(srcFile *os.File) {
-		_ = srcFile.Close()
-	}(srcFile)
+// renderCounts counts the renders of each icon, keyed by icon key
+var renderCounts sync.Map
 
-	destFile, err := os.Create(dest)
-	if err != nil {
-		return err
+// countRender counts a render of the given icon
+func countRender(name string, iconType core.IconType) {
+	key := fmt.Sprintf("%s/%s", iconType, name)
+	counter, ok := renderCounts.Load(key)
+	if !ok {
+		counter, _ = renderCounts.LoadOrStore(key, new(atomic.Int64))
 	}
+	counter.(*atomic.Int64).Add(1)
+}
 
-	defer func(destFile *os.File) {
-		_ = destFile.Close()
-	}(destFile)
+// RenderCounts returns the number of times each icon was rendered since the program
+// started, keyed by icon key (type/name), for usage reports (see heroicons.UsageReport)
+func RenderCounts() map[string]int64 {
+	counts := make(map[string]int64)
+	renderCounts.Range(func(key, counter any) bool {
+		counts[key.(string)] = counter.(*atomic.Int64).Load()
+		return true
+	})
+	return counts
+}
 
-	_, err = io.Copy(destFile, srcFile)
-	return err
+// deprecatedIcons maps the keys of deprecated icons to the keys of their replacements,
+// which are empty when there is no replacement
+var deprecatedIcons = map[string]string{
+{{- range $key, $replacement := .Deprecated }}
+	"{{ $key }}": "{{ $replacement }}",
+{{- end }}
 }
 
-const providerTemplate = `// Code generated by heroicons generator; DO NOT EDIT.
-package icons
+// deprecationWarned records the deprecated icons a warning was logged for
+var deprecationWarned sync.Map
 
-import (
-	"embed"
-	"fmt"
-	"html/template"
-	"strings"
+// checkDeprecated logs a warning the first time a deprecated icon is rendered, or returns
+// an error when StrictDeprecations is set
+func checkDeprecated(name string, iconType core.IconType) error {
+	key := fmt.Sprintf("%s/%s", iconType, name)
+	replacement, ok := deprecatedIcons[key]
+	if !ok {
+		return nil
+	}
 
-	"github.com/patrickward/go-heroicons"
-)
+	message := fmt.Sprintf("icon %s is deprecated", key)
+	if replacement != "" {
+		message += fmt.Sprintf("; use %s instead", replacement)
+	}
 
-const IconCustom = "custom"
+	if strictDeprecations.Get(StrictDeprecations) {
+		return fmt.Errorf("%s", message)
+	}
+	if _, warned := deprecationWarned.LoadOrStore(key, true); !warned {
+		log.Printf("heroicons: %s", message)
+	}
+	return nil
+}
 
-//go:embed {{.IconsDir}}/*.svg {{.CustomIconsDir}}/*.svg
-var iconFS embed.FS
+// addSource annotates the SVG with a data-hi-src attribute naming the call site that
+// rendered it
+func addSource(svg string) string {
+	source := template.HTMLEscapeString(core.CallSite())
+	return strings.Replace(svg, "<svg ", fmt.Sprintf("<svg data-hi-src=\"%s\" ", source), 1)
+}
 
-// FailOnError determines whether to use a generic missing icon when an icon is not found
-var FailOnError = {{ if .FailOnError }}true{{ else }}false{{ end }} 
+// ExternalReferences returns the external resources referenced by each embedded icon,
+// keyed by file name. Icons without external references are omitted, so an empty result
+// means rendering icons cannot introduce Content Security Policy violations.
+func ExternalReferences() (map[string][]string, error) {
+	paths, err := iconFilePaths()
+	if err != nil {
+		return nil, err
+	}
 
-// IconType represents the different types of Heroicons
-type IconType string
+	refs := make(map[string][]string)
+	for _, path := range paths {
+		content, err := readIconFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if found := core.ExternalReferences(content); len(found) > 0 {
+			refs[path] = found
+		}
+	}
+	return refs, nil
+}
 
-const (
-	IconOutline IconType = "outline" // 24px outline icons
-	IconSolid   IconType = "solid"   // 24px solid icons
-	IconMini    IconType = "mini"    // 20px solid icons
-	IconMicro   IconType = "micro"   // 16px solid icons
-)
+// Footprint reports the memory held by the embedded icons
+type Footprint struct {
+	// Icons is the number of bytes of embedded SVG content
+	Icons int64 ` + "`json:\"icons\"`" + `
+	// Manifest is the number of bytes of the keys and file names in the icon manifest
+	Manifest int64 ` + "`json:\"manifest\"`" + `
+}
 
-var iconPaths = map[string]string{
-{{- range $key, $path := .IconPaths }}
-	"{{ $key }}": "{{ $path }}",
-{{- end }}
+// Total returns the total number of bytes
+func (f Footprint) Total() int64 {
+	return f.Icons + f.Manifest
 }
 
-// RenderIcon returns the SVG content for the specified icon with added classes
-func RenderIcon(name string, iconType heroicons.IconType, class string) (template.HTML, error) {
-	svg, err := getIcon(name, iconType)
+// MemoryFootprint reports the memory held by the embedded icons and the icon manifest
+func MemoryFootprint() (Footprint, error) {
+	var footprint Footprint
+	for key, path := range iconPaths {
+		footprint.Manifest += int64(len(key) + len(path))
+	}
+
+	paths, err := iconFilePaths()
 	if err != nil {
-		return "", err
+		return footprint, err
 	}
 
-	// If class is provided, insert it into the SVG
-	if class != "" {
-		if strings.Contains(svg, "class=\"") {
-			svg = strings.Replace(svg, "class=\"", fmt.Sprintf("class=\"%s ", class), 1)
-		} else {
-			svg = strings.Replace(svg, "<svg ", fmt.Sprintf("<svg class=\"%s\" ", class), 1)
+	for _, path := range paths {
+		content, err := readIconFile(path)
+		if err != nil {
+			return footprint, err
 		}
+		footprint.Icons += int64(len(content))
 	}
+	return footprint, nil
+}
 
-	return template.HTML(svg), nil
+// fetchIcon returns the SVG content for the specified icon{{ if .ProfileLabels }}, labeling the work with the
+// icon name and type for CPU profiles when ProfileLabels is set{{ end }}
+func fetchIcon(name string, iconType core.IconType, onMissing core.MissingPolicy) (svg string, err error) {
+{{- if not .ProfileLabels }}
+	return getIcon(name, iconType, onMissing)
+{{- else }}
+	if !profileLabels.Get(ProfileLabels) {
+		return getIcon(name, iconType, onMissing)
+	}
+
+	labels := pprof.Labels("icon", name, "icon_type", string(iconType))
+	pprof.Do(context.Background(), labels, func(context.Context) {
+		svg, err = getIcon(name, iconType, onMissing)
+	})
+	return svg, err
+{{- end }}
 }
 
 func getMissingIcon() string {
-	content, err := iconFS.ReadFile("{{.CustomIconsDir}}/missing.svg")
+	content, err := readIconFile("{{.CustomIconsDir}}/missing.svg")
 	if err != nil {
 		return ""
 	}
 	return string(content)
 }
 
-func getIcon(name string, iconType heroicons.IconType) (string, error) {
+// iconFile returns the path of the file of the icon with the given key{{ if .Bundle }}, as listed in
+// the index of the bundle, so a new bundle can add, rename and remove icons without
+// recompiling. The manifest compiled into the package is used if no bundle can be loaded.{{ end }}
+func iconFile(key string) (string, bool) {
+{{- if .Bundle }}
+	if b, err := loadedBundle(); err == nil {
+		path, ok := b.Index.Icons[key]
+		return path, ok
+	}
+{{- end }}
+	filename, ok := iconPaths[key]
+	return "{{.IconsDir}}/" + filename, ok
+}
+
+// manifestKeys returns the keys of the icons{{ if .Bundle }} in the index of the bundle, or in the
+// compiled manifest if no bundle can be loaded{{ else }} in the manifest{{ end }}, unsorted
+func manifestKeys() []string {
+{{- if .Bundle }}
+	if b, err := loadedBundle(); err == nil {
+		keys := make([]string, 0, len(b.Index.Icons))
+		for key := range b.Index.Icons {
+			keys = append(keys, key)
+		}
+		return keys
+	}
+{{- end }}
+	keys := make([]string, 0, len(iconPaths))
+	for key := range iconPaths {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func getIcon(name string, iconType core.IconType, onMissing core.MissingPolicy) (string, error) {
 	if iconType == IconCustom {
 		// Look in custom directory 
-		content, err := iconFS.ReadFile(fmt.Sprintf("{{.CustomIconsDir}}/%s.svg", name))
+		content, err := readIconFile(fmt.Sprintf("{{.CustomIconsDir}}/%s.svg", name))
 		if err == nil {
 			return string(content), nil
 		} 
-	} else {
-		key := fmt.Sprintf("%s/%s", iconType, name)
-		if filename, ok := iconPaths[key]; ok {
-	        content, err := iconFS.ReadFile(fmt.Sprintf("{{.IconsDir}}/%s", filename))	
-			if err == nil {
-				return string(content), nil
-			}
+	}
+
+	key := fmt.Sprintf("%s/%s", iconType, name)
+	if path, ok := iconFile(key); ok {
+		content, err := readIconFile(path)
+		if err == nil {
+			return string(content), nil
 		}
 	}
 
-	// Fall back to missing icon if not found
-	if FailOnError {
+{{- if .DebugHandler }}
+
+	recordMissing(fmt.Sprintf("%s/%s", iconType, name))
+{{- end }}
+
+	if onMissing == core.MissingDefault {
+		onMissing = core.MissingFallback
+		if failOnError.Get(FailOnError) {
+			onMissing = core.MissingError
+		}
+	}
+
+	switch onMissing {
+	case core.MissingError:
 		return "", fmt.Errorf("icon not found: %s/%s", iconType, name)
+	case core.MissingEmpty:
+		return "", nil
 	}
 
+	// Fall back to missing icon if not found
 	return getMissingIcon(), nil
 }
+{{- if .DebugHandler }}
 
-func getIconOLD(name string, iconType heroicons.IconType) (string, error) {
-	key := fmt.Sprintf("%s/%s", iconType, name)
-	filename, ok := iconPaths[key]
-	if !ok {
-		if FailOnError {
-			return "", fmt.Errorf("icon not found: %s", key)
+// maxMissingEvents is the number of missing icon events kept for the debug handler
+const maxMissingEvents = 50
+
+// missingEvent records a request for an icon that could not be found
+type missingEvent struct {
+	Key  string    ` + "`json:\"key\"`" + `
+	Time time.Time ` + "`json:\"time\"`" + `
+}
+
+var (
+	missingMu     sync.Mutex
+	missingEvents []missingEvent
+)
+
+// recordMissing records a request for a missing icon, keeping the most recent events
+func recordMissing(key string) {
+	missingMu.Lock()
+	defer missingMu.Unlock()
+
+	missingEvents = append(missingEvents, missingEvent{Key: key, Time: time.Now()})
+	if len(missingEvents) > maxMissingEvents {
+		missingEvents = missingEvents[len(missingEvents)-maxMissingEvents:]
+	}
+}
+
+// DebugHandler returns an http.Handler that reports the number of embedded icons, their
+// memory footprint, the render count of each icon and the most recent requests for
+// missing icons as JSON. It is intended to be mounted under /debug.
+func DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		footprint, err := MemoryFootprint()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
-		return getMissingIcon(), nil
+
+		missingMu.Lock()
+		missing := append([]missingEvent(nil), missingEvents...)
+		missingMu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"icons":         len(manifestKeys()),
+			"footprint":     footprint,
+			"missing_icons": missing,
+			"render_counts": RenderCounts(),
+		})
+	})
+}
+{{- end }}
+
+{{- if .Sprite }}
+
+{{- if .InlineSVG }}
+
+var spriteSheet = {{ printf "%q" .SpriteSheet }}
+{{- else }}
+
+//go:embed {{.SpriteFile}}
+var spriteSheet string
+{{- end }}
+
+var spriteViewBoxes = map[string]string{
+{{- range $key, $viewBox := .SpriteViewBoxes }}
+	"{{ $key }}": "{{ $viewBox }}",
+{{- end }}
+}
+
+// SpriteSheet returns the sprite sheet with a symbol for every embedded icon. Include it
+// once per page, typically at the start of the body, before using SpriteIcon.
+func SpriteSheet() template.HTML {
+	return template.HTML(spriteSheet)
+}
+
+// SpriteIcon returns an SVG that references the icon's symbol in the sprite sheet, which
+// keeps pages small when the same icon appears many times. Icons that are not in the
+// sprite sheet are rendered inline, as with RenderIcon.
+func SpriteIcon(name string, iconType core.IconType, class string) (template.HTML, error) {
+	viewBox, ok := spriteViewBoxes[fmt.Sprintf("%s/%s", iconType, name)]
+	if !ok {
+		return RenderIcon(name, iconType, class)
 	}
 
-	filename = fmt.Sprintf("{{.IconsDir}}/%s", filename)
-	content, err := iconFS.ReadFile(filename)
-	if err != nil {
-		if FailOnError {
-			return "", fmt.Errorf("failed to read icon %s: %w", filename, err)
+	classAttr := ""
+	if class != "" {
+		classAttr = fmt.Sprintf(" class=\"%s\"", template.HTMLEscapeString(class))
+	}
+	return template.HTML(fmt.Sprintf("<svg%s viewBox=\"%s\" aria-hidden=\"true\"><use href=\"#%s\"/></svg>",
+		classAttr, viewBox, core.SpriteID(name, iconType))), nil
+}
+{{- end }}
+
+// Render returns the SVG content for the referenced icon with added classes, like
+// RenderIcon
+func Render(ref core.IconRef, class string) (template.HTML, error) {
+	return RenderIcon(ref.Name, ref.Type, class)
+}
+
+// Has reports whether the referenced icon is in the manifest
+func Has(ref core.IconRef) bool {
+	_, ok := iconFile(ref.Key())
+	return ok
+}
+
+// Refs returns the icons in the manifest, sorted by key
+func Refs() []core.IconRef {
+	keys := Keys()
+	refs := make([]core.IconRef, 0, len(keys))
+	for _, key := range keys {
+		if ref, err := core.ParseIconSet(key); err == nil {
+			refs = append(refs, ref)
 		}
-		return getMissingIcon(), nil
 	}
+	return refs
+}
 
-	return string(content), nil
-}`
+// Keys returns the keys (type/name) of the icons in the manifest, sorted
+func Keys() []string {
+	keys := manifestKeys()
+	sort.Strings(keys)
+	return keys
+}
 
-func (g *Generator) generateProvider(iconPaths map[string]string) error {
-	tmpl, err := template.New("provider").Parse(providerTemplate)
+{{- if .PickerHandler }}
+
+// PickerHandler returns an http.Handler that lists the embedded icons as paginated,
+// searchable JSON with previews, to back an icon picker (see iconhttp.PickerHandler)
+func PickerHandler() http.Handler {
+	return iconhttp.PickerHandler(Keys(), RenderIcon)
+}
+{{- end }}
+`
+
+const cspTestTemplate = `// Code generated by heroicons generator; DO NOT EDIT.
+package {{.PackageName}}
+
+import "testing"
+
+func TestIconsHaveNoExternalReferences(t *testing.T) {
+	refs, err := ExternalReferences()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, found := range refs {
+		t.Errorf("%s references external resources: %v", path, found)
+	}
+}
+`
+
+// generateCSPTest writes a test that fails when an embedded icon references an external
+// resource
+func (g *Generator) generateCSPTest() error {
+	tmpl, err := template.New("csp").Parse(cspTestTemplate)
 	if err != nil {
 		return err
 	}
 
-	f, err := os.Create(filepath.Join(g.OutputPath, "provider.go"))
+	return g.executeToFile(tmpl, "csp_test.go", map[string]string{"PackageName": g.packageName()})
+}
+
+func (g *Generator) generateProvider(iconPaths, spriteViewBoxes map[string]string) error {
+	tmpl, err := template.New("provider").Parse(providerTemplate)
 	if err != nil {
 		return err
 	}
 
-	defer func(f *os.File) {
-		_ = f.Close()
-	}(f)
+	var inlineFiles map[string]string
+	var spriteSheet string
+	if g.InlineSVG {
+		if inlineFiles, err = g.inlineFiles(iconPaths); err != nil {
+			return err
+		}
+		if g.Sprite {
+			content, err := fs.ReadFile(g.output(), SpriteFileName)
+			if err != nil {
+				return err
+			}
+			spriteSheet = string(content)
+		}
+	}
+
+	iconNames := g.iconNameConsts(iconPaths)
+	var accessors []iconConst
+	if g.Accessors {
+		accessors = g.iconAccessors(iconPaths, iconNames)
+	}
+
+	providerPaths := iconPaths
+	if g.BuildTags {
+		var tagged map[IconType]map[string]string
+		providerPaths, tagged = splitTagged(iconPaths)
+		if err := g.generateTypeFiles(tagged, inlineFiles); err != nil {
+			return err
+		}
+		for _, paths := range tagged {
+			for _, filename := range paths {
+				delete(inlineFiles, g.iconsDirName()+"/"+filename)
+			}
+		}
+	} else if err := g.removeTypeFiles(); err != nil {
+		return err
+	}
 
 	data := struct {
-		PackageName    string
-		IconsDir       string
-		CustomIconsDir string
-		IconPaths      map[string]string
-		FailOnError    bool
+		PackageName        string
+		IconsDir           string
+		CustomIconsDir     string
+		IconPaths          map[string]string
+		EmbedPatterns      string
+		BuildTags          bool
+		IconNames          []iconConst
+		Accessors          []iconConst
+		FailOnError        bool
+		Deprecated         map[string]string
+		StrictDeprecations bool
+		Sprite             bool
+		SpriteFile         string
+		SpriteViewBoxes    map[string]string
+		SpriteSheet        string
+		InlineSVG          bool
+		InlineFiles        map[string]string
+		Bundle             bool
+		BundleFile         string
+		DebugHandler       bool
+		PickerHandler      bool
+		ProfileLabels      bool
 	}{
-		PackageName:    g.PackageName,
-		IconsDir:       iconsDir,
-		CustomIconsDir: customIconsDir,
-		IconPaths:      iconPaths,
-		FailOnError:    g.FailOnError,
+		PackageName:        g.packageName(),
+		IconsDir:           g.iconsDirName(),
+		CustomIconsDir:     customIconsDir,
+		IconPaths:          providerPaths,
+		EmbedPatterns:      g.embedPatterns(iconPaths),
+		BuildTags:          g.BuildTags,
+		IconNames:          iconNames,
+		Accessors:          accessors,
+		FailOnError:        g.FailOnError,
+		Deprecated:         g.Deprecated,
+		StrictDeprecations: g.StrictDeprecations,
+		Sprite:             g.Sprite,
+		SpriteFile:         SpriteFileName,
+		SpriteViewBoxes:    spriteViewBoxes,
+		SpriteSheet:        spriteSheet,
+		InlineSVG:          g.InlineSVG,
+		InlineFiles:        inlineFiles,
+		Bundle:             g.Bundle != "",
+		BundleFile:         g.bundleFile(),
+		DebugHandler:       g.DebugHandler,
+		PickerHandler:      g.PickerHandler,
+		ProfileLabels:      g.ProfileLabels,
+	}
+
+	return g.executeToFile(tmpl, g.providerFile(), data)
+}
+
+// inlineFiles returns the content of the icon files the provider embeds, keyed by their
+// path relative to the output directory
+func (g *Generator) inlineFiles(iconPaths map[string]string) (map[string]string, error) {
+	files := make(map[string]string)
+	out := g.output()
+	for _, filename := range iconPaths {
+		name := g.iconsDirName() + "/" + filename
+		content, err := fs.ReadFile(out, name)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = string(content)
+	}
+
+	customFiles, err := fs.Glob(out, customIconsDir+"/*.svg")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range customFiles {
+		content, err := fs.ReadFile(out, name)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = string(content)
 	}
 
-	return tmpl.Execute(f, data)
+	return files, nil
 }
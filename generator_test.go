@@ -0,0 +1,150 @@
+package heroicons
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testSVG returns the content of a test icon, distinct for each label
+func testSVG(label string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path d="M0 0"/><title>` + label + `</title></svg>`)}
+}
+
+// testSource returns a heroicons repository holding the given outline icons, keyed by
+// name, with the content of testSVG(content)
+func testSource(icons map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{
+		"package.json": &fstest.MapFile{Data: []byte(`{"version": "2.2.0"}`)},
+	}
+	for name, content := range icons {
+		fsys["optimized/24/outline/"+name+".svg"] = testSVG(content)
+	}
+	return fsys
+}
+
+// newTestGenerator returns a generator reading from source and writing to out
+func newTestGenerator(source fs.FS, out *MemFS, icons ...string) *Generator {
+	g := &Generator{
+		SourceFS:   source,
+		OutputPath: "icons",
+		Output:     out,
+		Log:        io.Discard,
+	}
+	for _, name := range icons {
+		g.Icons = append(g.Icons, IconSet{Name: name, Type: IconOutline})
+	}
+	return g
+}
+
+// readOutput returns the content of the named file of out
+func readOutput(t *testing.T, out *MemFS, name string) string {
+	t.Helper()
+	data, err := fs.ReadFile(out, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestGenerateWritesProvider(t *testing.T) {
+	out := &MemFS{}
+	g := newTestGenerator(testSource(map[string]string{"home": "home", "bell": "bell"}), out, "home", "bell", "nope")
+
+	report, err := g.GenerateReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(report.Icons, ","); got != "outline/bell,outline/home" {
+		t.Errorf("icons = %s", got)
+	}
+	if got := strings.Join(report.Missing, ","); got != "outline/nope" {
+		t.Errorf("missing = %s", got)
+	}
+
+	provider := readOutput(t, out, "provider.go")
+	for _, want := range []string{"package icons", `"outline/home": "outline_home.svg"`, `"outline/bell": "outline_bell.svg"`} {
+		if !strings.Contains(provider, want) {
+			t.Errorf("provider.go does not contain %q", want)
+		}
+	}
+	readOutput(t, out, "icons/outline_home.svg")
+	readOutput(t, out, "custom/missing.svg")
+}
+
+// containsAll reports whether s contains every one of substrs
+func containsAll(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGenerateClearAndMerge(t *testing.T) {
+	out := &MemFS{}
+	g := newTestGenerator(testSource(map[string]string{"home": "home", "bell": "bell"}), out, "home", "bell")
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Merge keeps icons that are no longer configured and existing content
+	g = newTestGenerator(testSource(map[string]string{"home": "changed"}), out, "home")
+	g.Merge = true
+	report, err := g.GenerateReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(report.Icons, ","); got != "outline/bell,outline/home" {
+		t.Errorf("merged icons = %s", got)
+	}
+	if got := strings.Join(report.Conflicts, ","); got != "outline/home" {
+		t.Errorf("conflicts = %s", got)
+	}
+	if !strings.Contains(readOutput(t, out, "icons/outline_home.svg"), "<title>home</title>") {
+		t.Error("Merge overwrote an existing icon")
+	}
+
+	// ClearIcons removes icons that are no longer configured
+	g = newTestGenerator(testSource(map[string]string{"home": "home"}), out, "home")
+	g.ClearIcons = true
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Stat(out, "icons/outline_bell.svg"); err == nil {
+		t.Error("ClearIcons kept a removed icon")
+	}
+}
+
+func TestGenerateAll(t *testing.T) {
+	source := testSource(map[string]string{"home": "home", "bell": "bell"})
+	var generators []*Generator
+	var outputs []*MemFS
+	for i := range 4 {
+		out := &MemFS{}
+		g := newTestGenerator(source, out, "home", "bell")
+		g.OutputPath = fmt.Sprintf("icons%d", i)
+		generators = append(generators, g)
+		outputs = append(outputs, out)
+	}
+
+	reports, err := GenerateAll(generators...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, report := range reports {
+		if len(report.Icons) != 2 {
+			t.Errorf("generator %d: icons = %v", i, report.Icons)
+		}
+		readOutput(t, outputs[i], "provider.go")
+	}
+
+	generators[1].OutputPath = generators[0].OutputPath
+	if _, err := GenerateAll(generators...); err == nil {
+		t.Error("GenerateAll accepted generators writing to the same output")
+	}
+}
@@ -0,0 +1,82 @@
+// Package heroiconstest provides helpers for snapshot testing rendered icons.
+package heroiconstest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/patrickward/go-heroicons/core"
+)
+
+// update rewrites golden files instead of comparing against them (go test
+// -heroicons.update). The flag is namespaced so it does not clash with an -update flag
+// defined by the test package itself.
+var update = flag.Bool("heroicons.update", false, "update heroicons golden files")
+
+// Case is a single icon rendering to snapshot
+type Case struct {
+	Name  string
+	Type  core.IconType
+	Class string
+}
+
+// filename returns the golden file name for the case
+func (c Case) filename() string {
+	name := string(c.Type) + "_" + c.Name
+	if c.Class != "" {
+		name += "_" + slug(c.Class)
+	}
+	return name + ".golden"
+}
+
+// Golden renders each case with render and compares the output against the golden
+// files in dir. When the test binary is run with -heroicons.update, the golden files
+// are written instead.
+func Golden(t testing.TB, dir string, render core.RenderFunc, cases []Case) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+	}
+
+	for _, c := range cases {
+		got, err := render(c.Name, c.Type, c.Class)
+		if err != nil {
+			t.Errorf("%s/%s: render failed: %v", c.Type, c.Name, err)
+			continue
+		}
+
+		path := filepath.Join(dir, c.filename())
+		if *update {
+			if err := os.WriteFile(path, []byte(string(got)+"\n"), 0644); err != nil {
+				t.Errorf("%s/%s: failed to write golden file: %v", c.Type, c.Name, err)
+			}
+			continue
+		}
+
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("%s/%s: failed to read golden file (run with -heroicons.update to create it): %v", c.Type, c.Name, err)
+			continue
+		}
+
+		if string(got)+"\n" != string(want) {
+			t.Errorf("%s/%s: output does not match %s\ngot:\n%s\nwant:\n%s", c.Type, c.Name, path, got, want)
+		}
+	}
+}
+
+// slug converts a class list into a string safe for use in file names
+func slug(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '-'
+	}, s)
+}
@@ -0,0 +1,22 @@
+package heroiconstest
+
+import (
+	"flag"
+	"html/template"
+	"testing"
+
+	"github.com/patrickward/go-heroicons/core"
+)
+
+// A test package can define its own -update flag alongside the golden helper
+var _ = flag.Bool("update", false, "update the test package's own files")
+
+func TestGolden(t *testing.T) {
+	render := func(name string, iconType core.IconType, class string) (template.HTML, error) {
+		return template.HTML(`<svg class="` + class + `" data-icon="` + string(iconType) + "/" + name + `"></svg>`), nil
+	}
+	Golden(t, "testdata", render, []Case{
+		{Name: "home", Type: core.IconOutline},
+		{Name: "bell", Type: core.IconSolid, Class: "w-6 h-6"},
+	})
+}
@@ -0,0 +1,52 @@
+package heroicons
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// webhookTimeout bounds the time spent delivering the report to the webhook
+const webhookTimeout = 30 * time.Second
+
+// runHooks runs the post-generate command and delivers the report to the webhook, if
+// configured, with the report as a JSON payload
+func (g *Generator) runHooks(report *Report) error {
+	if len(g.PostGenerate) == 0 && g.Webhook == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	if len(g.PostGenerate) > 0 {
+		cmd := exec.Command(g.PostGenerate[0], g.PostGenerate[1:]...)
+		cmd.Stdin = bytes.NewReader(payload)
+		// Output goes to the log, not stdout, which may carry the JSON report
+		cmd.Stdout = g.logWriter()
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to run post-generate command: %w", err)
+		}
+	}
+
+	if g.Webhook != "" {
+		client := &http.Client{Timeout: webhookTimeout}
+		resp, err := client.Post(g.Webhook, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("failed to call webhook: %w", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("failed to call webhook: %s", resp.Status)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package heroicons
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestHookHelper is run as the post-generate command by TestPostGenerateOutput
+func TestHookHelper(t *testing.T) {
+	if os.Getenv("HEROICONS_TEST_HOOK") != "1" {
+		t.Skip("only run as a post-generate command")
+	}
+	report, _ := io.ReadAll(os.Stdin)
+	fmt.Printf("hook received %d bytes\n", len(report))
+	os.Exit(0)
+}
+
+func TestPostGenerateOutput(t *testing.T) {
+	t.Setenv("HEROICONS_TEST_HOOK", "1")
+
+	var log bytes.Buffer
+	g := newTestGenerator(testSource(map[string]string{"home": "home"}), &MemFS{}, "home")
+	g.Log = &log
+	g.PostGenerate = []string{os.Args[0], "-test.run=^TestHookHelper$"}
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(log.String(), "hook received") {
+		t.Errorf("log does not contain the command output:\n%s", log.String())
+	}
+}
@@ -0,0 +1,155 @@
+// Package iconbundle reads icon bundles: archives of icon files with an index, which
+// generated packages built with the Bundle option load at runtime instead of embedding
+// their icons.
+package iconbundle
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+)
+
+// IndexName is the name of the index file in an icon bundle
+const IndexName = "index.json"
+
+// Index lists the icons of a bundle
+type Index struct {
+	// Version is the version of the icon source, if known
+	Version string `json:"version,omitempty"`
+	// Icons maps each icon key (type/name) to the path of its file in the bundle
+	Icons map[string]string `json:"icons"`
+}
+
+// Bundle is an archive of icon files with an index, read by generated packages that ship
+// their icons next to the binary instead of embedding them (see Generator.Bundle in the
+// heroicons package). The files are read into memory when the bundle is opened, so a
+// bundle is safe for concurrent use and does not hold the archive open.
+type Bundle struct {
+	// Index lists the icons of the bundle
+	Index Index
+	files map[string][]byte
+}
+
+// Open reads the zip or tar bundle at path. Gzip-compressed tar bundles are accepted. It
+// returns an error if the index is missing or lists a file that is not in the bundle.
+func Open(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := readBundleFiles(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle %s: %w", path, err)
+	}
+
+	index, ok := files[IndexName]
+	if !ok {
+		return nil, fmt.Errorf("bundle %s has no %s", path, IndexName)
+	}
+	b := &Bundle{files: files}
+	if err := json.Unmarshal(index, &b.Index); err != nil {
+		return nil, fmt.Errorf("failed to parse the index of bundle %s: %w", path, err)
+	}
+	for key, file := range b.Index.Icons {
+		if _, ok := files[file]; !ok {
+			return nil, fmt.Errorf("bundle %s: icon %s: %s is not in the bundle", path, key, file)
+		}
+	}
+	return b, nil
+}
+
+// readBundleFiles returns the files of a zip, tar or gzip-compressed tar archive, keyed
+// by path
+func readBundleFiles(data []byte) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			content, err := readZipFile(f)
+			if err != nil {
+				return nil, err
+			}
+			files[path.Clean(f.Name)] = content
+		}
+		return files, nil
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if data, err = io.ReadAll(gz); err != nil {
+			return nil, err
+		}
+	}
+
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[path.Clean(header.Name)] = content
+	}
+}
+
+// readZipFile returns the content of a file in a zip archive
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(rc io.ReadCloser) {
+		_ = rc.Close()
+	}(rc)
+
+	return io.ReadAll(rc)
+}
+
+// ReadFile returns the content of the named file in the bundle
+func (b *Bundle) ReadFile(name string) ([]byte, error) {
+	content, ok := b.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return content, nil
+}
+
+// Files returns the paths of the files in the bundle, excluding the index, sorted
+func (b *Bundle) Files() []string {
+	names := make([]string, 0, len(b.files))
+	for name := range b.files {
+		if name != IndexName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
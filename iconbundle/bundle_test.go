@@ -0,0 +1,134 @@
+package iconbundle
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testFiles are the files of a valid bundle
+var testFiles = map[string]string{
+	IndexName:                `{"version": "2.2.0", "icons": {"outline/home": "icons/outline_home.svg"}}`,
+	"icons/outline_home.svg": "<svg>home</svg>",
+	"custom/missing.svg":     "<svg>missing</svg>",
+}
+
+// writeZip writes files to a zip bundle in dir and returns its path
+func writeZip(t *testing.T, dir string, files map[string]string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return writeBundle(t, dir, "bundle.zip", buf.Bytes())
+}
+
+// writeTar writes files to a tar bundle in dir, gzip-compressed if compress is set, and
+// returns its path
+func writeTar(t *testing.T, dir string, files map[string]string, compress bool) string {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !compress {
+		return writeBundle(t, dir, "bundle.tar", buf.Bytes())
+	}
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	if _, err := zw.Write(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return writeBundle(t, dir, "bundle.tar.gz", gz.Bytes())
+}
+
+// writeBundle writes data to the named file in dir and returns its path
+func writeBundle(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{
+		writeZip(t, dir, testFiles),
+		writeTar(t, dir, testFiles, false),
+		writeTar(t, dir, testFiles, true),
+	} {
+		b, err := Open(path)
+		if err != nil {
+			t.Errorf("%s: %v", filepath.Base(path), err)
+			continue
+		}
+		if b.Index.Version != "2.2.0" || b.Index.Icons["outline/home"] != "icons/outline_home.svg" {
+			t.Errorf("%s: index = %+v", filepath.Base(path), b.Index)
+		}
+		content, err := b.ReadFile("icons/outline_home.svg")
+		if err != nil || string(content) != "<svg>home</svg>" {
+			t.Errorf("%s: ReadFile = %q, %v", filepath.Base(path), content, err)
+		}
+		if _, err := b.ReadFile("icons/nope.svg"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("%s: reading a missing file: got %v, want fs.ErrNotExist", filepath.Base(path), err)
+		}
+		if got, want := b.Files(), []string{"custom/missing.svg", "icons/outline_home.svg"}; !slices.Equal(got, want) {
+			t.Errorf("%s: Files() = %v, want %v", filepath.Base(path), got, want)
+		}
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	dir := t.TempDir()
+	missing := map[string]string{IndexName: `{"icons": {"outline/home": "icons/outline_home.svg"}}`}
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"missing file", filepath.Join(dir, "nope.zip"), "nope.zip"},
+		{"no index", writeTar(t, dir, map[string]string{"icons/outline_home.svg": "<svg/>"}, false), "has no index.json"},
+		{"invalid index", writeZip(t, dir, map[string]string{IndexName: "{"}), "failed to parse the index"},
+		{"not an archive", writeBundle(t, dir, "text.zip", []byte("not an archive")), "failed to read bundle"},
+		{"listed file missing", writeTar(t, dir, missing, true), "icons/outline_home.svg is not in the bundle"},
+	}
+	for _, tt := range tests {
+		_, err := Open(tt.path)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %v, want an error containing %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package iconhttp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/patrickward/go-heroicons/core"
+)
+
+// assetHashLength is the number of hex digits of the content hash in asset file names
+const assetHashLength = 10
+
+// Assets serves icons as SVG files at content-hashed paths, such as
+// /icons/outline-home.9f3a2c1b7d.svg, so they can be cached forever by browsers and CDNs:
+// a changed icon gets a new path. The icons are rendered once, when the Assets are
+// created; create new Assets after reloading a bundle.
+type Assets struct {
+	prefix string
+	// files maps the file names to the icon content
+	files map[string][]byte
+	// names maps the icon keys to their file names
+	names map[string]string
+}
+
+// NewAssets renders the icons with the given keys (type/name) with render and returns
+// Assets whose URLs start with prefix, the path the Handler is mounted at.
+func NewAssets(prefix string, keys []string, render core.RenderFunc) (*Assets, error) {
+	a := &Assets{
+		prefix: strings.TrimSuffix(prefix, "/"),
+		files:  make(map[string][]byte, len(keys)),
+		names:  make(map[string]string, len(keys)),
+	}
+	for _, key := range keys {
+		icon, err := core.ParseIconSet(key)
+		if err != nil {
+			return nil, err
+		}
+		svg, err := render(icon.Name, icon.Type, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", key, err)
+		}
+
+		sum := sha256.Sum256([]byte(svg))
+		name := fmt.Sprintf("%s-%s.%s.svg", icon.Type, icon.Name, hex.EncodeToString(sum[:])[:assetHashLength])
+		a.files[name] = []byte(svg)
+		a.names[key] = name
+	}
+	return a, nil
+}
+
+// URL returns the content-hashed URL of the icon
+func (a *Assets) URL(name string, iconType core.IconType) (string, error) {
+	key := core.IconSet{Name: name, Type: iconType}.Key()
+	file, ok := a.names[key]
+	if !ok {
+		return "", fmt.Errorf("icon %s is not served", key)
+	}
+	return a.prefix + "/" + file, nil
+}
+
+// FuncMap returns the "iconURL" template function, which calls URL:
+//
+//	<img src="{{ iconURL "home" "outline" }}" alt="">
+func (a *Assets) FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"iconURL": func(name string, iconType core.IconType) (string, error) {
+			return a.URL(name, iconType)
+		},
+	}
+}
+
+// Handler returns an http.Handler serving the icons by the last element of the request
+// path, with headers allowing them to be cached forever. Unknown and outdated paths are
+// not found.
+func (a *Assets) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := a.files[path.Base(r.URL.Path)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		// An SVG opened directly is a document; keep it from running anything
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+		_, _ = w.Write(content)
+	})
+}
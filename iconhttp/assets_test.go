@@ -0,0 +1,70 @@
+package iconhttp
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/patrickward/go-heroicons/core"
+)
+
+func TestAssets(t *testing.T) {
+	assets, err := NewAssets("/icons/", []string{"outline/home", "solid/bell"}, rawRender)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := assets.URL("home", core.IconOutline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^/icons/outline-home\.[0-9a-f]{10}\.svg$`).MatchString(url) {
+		t.Errorf("URL = %q, want a content-hashed path", url)
+	}
+	if _, err := assets.URL("home", core.IconSolid); err == nil {
+		t.Error("URL of an icon that is not served succeeded")
+	}
+
+	handler := assets.Handler()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `data-icon="outline/home"`) {
+		t.Fatalf("GET %s: status %d, body %q", url, rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Cache-Control"); !strings.Contains(got, "immutable") {
+		t.Errorf("Cache-Control = %q, want immutable", got)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "image/svg+xml" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	// A path with an outdated hash is not found
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/icons/outline-home.0000000000.svg", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("outdated hash: status %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	// The hash changes with the content
+	changed, err := NewAssets("/icons", []string{"outline/home"}, func(name string, iconType core.IconType, class string) (template.HTML, error) {
+		return `<svg data-version="2"></svg>`, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changedURL, _ := changed.URL("home", core.IconOutline); changedURL == url {
+		t.Errorf("changed icon kept the URL %s", url)
+	}
+
+	var b strings.Builder
+	tmpl := template.Must(template.New("").Funcs(assets.FuncMap()).Parse(`<img src="{{ iconURL "bell" "solid" }}">`))
+	if err := tmpl.Execute(&b, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "/icons/solid-bell.") {
+		t.Errorf("template rendered %q", b.String())
+	}
+}
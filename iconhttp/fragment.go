@@ -0,0 +1,106 @@
+// Package iconhttp provides HTTP handlers serving icons rendered by a generated package:
+// HTML fragments for htmx-style swaps, a JSON API for icon pickers and SVG files at
+// content-hashed paths.
+package iconhttp
+
+import (
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/patrickward/go-heroicons/core"
+)
+
+// FragmentHandler returns an http.Handler that renders a single icon as an HTML fragment,
+// suitable for swapping into a page with htmx (hx-get) or similar libraries. The icon is
+// selected with the "name", "type" and "class" query parameters; the type defaults to
+// outline. Classes containing quotes or angle brackets are rejected. A panic in render is
+// recovered and answered with an internal server error.
+func FragmentHandler(render core.RenderFunc) http.Handler {
+	render = core.RecoverRender(render)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		name := query.Get("name")
+		if name == "" {
+			http.Error(w, "missing icon name", http.StatusBadRequest)
+			return
+		}
+
+		iconType := core.IconType(query.Get("type"))
+		if iconType == "" {
+			iconType = core.IconOutline
+		}
+
+		// Generated packages escape classes, but other render funcs may insert them as is
+		class := query.Get("class")
+		if strings.ContainsAny(class, `"<>`) {
+			http.Error(w, "invalid class", http.StatusBadRequest)
+			return
+		}
+
+		svg, err := render(name, iconType, class)
+		if err != nil {
+			renderError(w, err)
+			return
+		}
+
+		WriteFragment(w, svg)
+	})
+}
+
+// renderError answers a request whose icon failed to render: not found, unless the render
+// func panicked. The panic value may hold internal details, so it is not sent.
+func renderError(w http.ResponseWriter, err error) {
+	if errors.Is(err, core.ErrPanic) {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusNotFound)
+}
+
+// WriteFragment writes the given markup as an HTML fragment response
+func WriteFragment(w http.ResponseWriter, html template.HTML) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = io.WriteString(w, string(html))
+}
+
+// Toggle describes an icon with two states, such as a bookmark that is either set or
+// unset. Rendering the whole toggle for a state, rather than patching the SVG, lets the
+// client swap between the states in a single atomic replacement.
+type Toggle struct {
+	// On is the icon rendered when the toggle is on
+	On core.IconSet
+	// Off is the icon rendered when the toggle is off
+	Off core.IconSet
+	// Class is applied to the icon in both states
+	Class string
+}
+
+// Render renders the icon for the given state
+func (t Toggle) Render(render core.RenderFunc, on bool) (template.HTML, error) {
+	icon := t.Off
+	if on {
+		icon = t.On
+	}
+	return render(icon.Name, icon.Type, t.Class)
+}
+
+// Handler returns an http.Handler that renders the toggle as an HTML fragment for the
+// state given by the "state" query parameter ("on" or "off"), recovering a panic in
+// render like FragmentHandler. Applications that store the state themselves can call
+// Render and WriteFragment from their own handlers.
+func (t Toggle) Handler(render core.RenderFunc) http.Handler {
+	render = core.RecoverRender(render)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		svg, err := t.Render(render, r.URL.Query().Get("state") == "on")
+		if err != nil {
+			renderError(w, err)
+			return
+		}
+
+		WriteFragment(w, svg)
+	})
+}
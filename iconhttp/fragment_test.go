@@ -0,0 +1,63 @@
+package iconhttp
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/patrickward/go-heroicons/core"
+)
+
+// rawRender inserts the class without escaping, like a careless render func would
+func rawRender(name string, iconType core.IconType, class string) (template.HTML, error) {
+	return template.HTML(`<svg class="` + class + `" data-icon="` + string(iconType) + "/" + name + `"></svg>`), nil
+}
+
+func TestFragmentHandler(t *testing.T) {
+	tests := []struct {
+		query string
+		code  int
+		want  string
+	}{
+		{"name=home", http.StatusOK, `data-icon="outline/home"`},
+		{"name=bell&type=solid&class=w-5+md:w-6", http.StatusOK, `class="w-5 md:w-6" data-icon="solid/bell"`},
+		{"", http.StatusBadRequest, "missing icon name"},
+		{"name=home&class=%22%3E%3Cscript%3E", http.StatusBadRequest, "invalid class"},
+		{"name=home&class=x%22+onload%3D%22y", http.StatusBadRequest, "invalid class"},
+	}
+
+	handler := FragmentHandler(rawRender)
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil))
+		if rec.Code != tt.code {
+			t.Errorf("%q: status %d, want %d", tt.query, rec.Code, tt.code)
+		}
+		if !strings.Contains(rec.Body.String(), tt.want) {
+			t.Errorf("%q: body %q does not contain %q", tt.query, rec.Body.String(), tt.want)
+		}
+	}
+}
+
+func TestHandlersRecoverPanics(t *testing.T) {
+	panicking := func(name string, iconType core.IconType, class string) (template.HTML, error) {
+		panic("secret internal state")
+	}
+	handlers := map[string]http.Handler{
+		"fragment": FragmentHandler(panicking),
+		"toggle":   Toggle{On: core.IconSet{Name: "bell", Type: core.IconSolid}}.Handler(panicking),
+		"picker":   PickerHandler([]string{"outline/home"}, panicking),
+	}
+	for name, handler := range handlers {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?name=home&state=on", nil))
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("%s: status %d, want %d", name, rec.Code, http.StatusInternalServerError)
+		}
+		if strings.Contains(rec.Body.String(), "secret") {
+			t.Errorf("%s: body %q exposes the panic value", name, rec.Body.String())
+		}
+	}
+}
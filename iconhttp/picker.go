@@ -0,0 +1,125 @@
+package iconhttp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/patrickward/go-heroicons/core"
+)
+
+const (
+	// DefaultPickerPageSize is the number of icons per page returned by PickerHandler
+	DefaultPickerPageSize = 50
+	// maxPickerPageSize is the largest page size a client can request
+	maxPickerPageSize = 500
+)
+
+// PickerIcon is an icon listed by PickerHandler
+type PickerIcon struct {
+	Key     string        `json:"key"`
+	Name    string        `json:"name"`
+	Type    core.IconType `json:"type"`
+	Preview string        `json:"preview"`
+}
+
+// PickerPage is a page of icons returned by PickerHandler
+type PickerPage struct {
+	Icons   []PickerIcon `json:"icons"`
+	Total   int          `json:"total"`
+	Page    int          `json:"page"`
+	PerPage int          `json:"per_page"`
+}
+
+// PickerHandler returns an http.Handler that lists the icons with the given keys
+// (type/name) as paginated JSON, to back an icon picker in admin UIs. Each icon includes
+// a data URI preview rendered with render. The "q" query parameter filters icons whose
+// name contains it, "type" filters by icon type, and "page" (from 1) and "per_page"
+// select the page. A panic in render is recovered and answered with an internal server
+// error.
+func PickerHandler(keys []string, render core.RenderFunc) http.Handler {
+	render = core.RecoverRender(render)
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		page, err := pickerParam(query.Get("page"), 1)
+		if err != nil {
+			http.Error(w, "invalid page", http.StatusBadRequest)
+			return
+		}
+		perPage, err := pickerParam(query.Get("per_page"), DefaultPickerPageSize)
+		if err != nil {
+			http.Error(w, "invalid per_page", http.StatusBadRequest)
+			return
+		}
+		perPage = min(perPage, maxPickerPageSize)
+
+		search := strings.ToLower(query.Get("q"))
+		iconType := core.IconType(query.Get("type"))
+
+		var matches []core.IconSet
+		for _, key := range sorted {
+			icon, err := core.ParseIconSet(key)
+			if err != nil {
+				continue
+			}
+			if iconType != "" && icon.Type != iconType {
+				continue
+			}
+			if search != "" && !strings.Contains(icon.Name, search) {
+				continue
+			}
+			matches = append(matches, icon)
+		}
+
+		result := PickerPage{
+			Icons:   []PickerIcon{},
+			Total:   len(matches),
+			Page:    page,
+			PerPage: perPage,
+		}
+
+		start := min((page-1)*perPage, len(matches))
+		end := min(start+perPage, len(matches))
+		for _, icon := range matches[start:end] {
+			svg, err := render(icon.Name, icon.Type, "")
+			if err != nil {
+				// The panic value may hold internal details
+				message := err.Error()
+				if errors.Is(err, core.ErrPanic) {
+					message = http.StatusText(http.StatusInternalServerError)
+				}
+				http.Error(w, message, http.StatusInternalServerError)
+				return
+			}
+			result.Icons = append(result.Icons, PickerIcon{
+				Key:     icon.Key(),
+				Name:    icon.Name,
+				Type:    icon.Type,
+				Preview: "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(svg)),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+	})
+}
+
+// pickerParam parses a positive integer query parameter, returning def when it is empty
+func pickerParam(value string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, strconv.ErrSyntax
+	}
+	return n, nil
+}
@@ -0,0 +1,62 @@
+package heroicons
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// defaultPackageName is the name of the generated package when PackageName is empty
+	defaultPackageName = "icons"
+	// defaultProviderFile is the name of the generated provider file when ProviderFile is
+	// empty
+	defaultProviderFile = "provider.go"
+)
+
+// packageName returns the name of the generated package
+func (g *Generator) packageName() string {
+	if g.PackageName == "" {
+		return defaultPackageName
+	}
+	return g.PackageName
+}
+
+// iconsDirName returns the name of the directory the icons are copied into
+func (g *Generator) iconsDirName() string {
+	if g.IconsDir == "" {
+		return iconsDir
+	}
+	return g.IconsDir
+}
+
+// outputFile returns the path in OutputPath of the named output file, for reporting
+func (g *Generator) outputFile(name string) string {
+	return filepath.Join(g.OutputPath, filepath.FromSlash(name))
+}
+
+// providerFile returns the name of the generated provider file
+func (g *Generator) providerFile() string {
+	if g.ProviderFile == "" {
+		return defaultProviderFile
+	}
+	return g.ProviderFile
+}
+
+// validateLayout checks the configured names of the generated directory and file
+func (g *Generator) validateLayout() []error {
+	var errs []error
+	if g.IconsDir != "" {
+		if !filepath.IsLocal(g.IconsDir) || strings.ContainsAny(g.IconsDir, `/\`) || strings.HasPrefix(g.IconsDir, ".") {
+			errs = append(errs, fmt.Errorf("IconsDir %q must be the name of a directory in OutputPath", g.IconsDir))
+		} else if g.IconsDir == customIconsDir {
+			errs = append(errs, fmt.Errorf("IconsDir cannot be %q: it is used for custom icons", customIconsDir))
+		}
+	}
+	if g.ProviderFile != "" {
+		if filepath.Base(g.ProviderFile) != g.ProviderFile || filepath.Ext(g.ProviderFile) != ".go" || strings.HasSuffix(g.ProviderFile, "_test.go") {
+			errs = append(errs, fmt.Errorf("ProviderFile %q must be the name of a non-test .go file", g.ProviderFile))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,75 @@
+package heroicons
+
+import (
+	"io/fs"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// linkedFuncsFile is the name of the file holding the per-icon functions
+const linkedFuncsFile = "linked.go"
+
+const linkedTemplate = `// Code generated by heroicons generator; DO NOT EDIT.
+
+package {{.PackageName}}
+
+import "html/template"
+
+// The functions in this file hold the content of their icon, so icons that are never
+// referenced are removed by the linker. Use them instead of RenderIcon to keep binaries
+// small: calling RenderIcon, Icon or any other lookup by name links every icon.
+{{- range .Funcs }}
+
+// {{ .Ident }} renders the {{ .Key }} icon with the given classes
+func {{ .Ident }}(class string) template.HTML {
+	return template.HTML(addClass({{ printf "%q" .SVG }}, class))
+}
+{{- end }}
+`
+
+// linkedFunc is a generated function holding the content of an icon
+type linkedFunc struct {
+	Ident string
+	Key   string
+	SVG   string
+}
+
+// generateLinkedFuncs writes a function per icon, such as OutlineHome(class), that holds
+// the icon's content, so the linker can drop icons that are never referenced
+func (g *Generator) generateLinkedFuncs(iconPaths map[string]string) error {
+	seen := make(map[string]string)
+	var funcs []linkedFunc
+	for key, filename := range iconPaths {
+		iconType, name, _ := strings.Cut(key, "/")
+		ident := exportedName(iconType) + exportedName(name)
+		if providerIdents[ident] || seen[ident] != "" {
+			continue
+		}
+		seen[ident] = key
+
+		content, err := fs.ReadFile(g.output(), g.iconsDirName()+"/"+filename)
+		if err != nil {
+			return err
+		}
+		funcs = append(funcs, linkedFunc{Ident: ident, Key: key, SVG: string(content)})
+	}
+	sort.Slice(funcs, func(i, j int) bool {
+		return funcs[i].Ident < funcs[j].Ident
+	})
+
+	tmpl, err := template.New("linked").Parse(linkedTemplate)
+	if err != nil {
+		return err
+	}
+
+	return g.executeToFile(tmpl, linkedFuncsFile, map[string]any{
+		"PackageName": g.packageName(),
+		"Funcs":       funcs,
+	})
+}
+
+// removeLinkedFuncs removes a previously generated linked.go
+func (g *Generator) removeLinkedFuncs() error {
+	return g.output().RemoveAll(linkedFuncsFile)
+}
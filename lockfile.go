@@ -0,0 +1,200 @@
+package heroicons
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+)
+
+// LockfileName is the name of the lockfile written to the output directory
+const LockfileName = "heroicons.lock"
+
+// ErrLockfileMismatch is returned when the source version or the content of a locked icon
+// differs from the lockfile. Set Generator.UpdateLockfile to accept the changes.
+var ErrLockfileMismatch = errors.New("icons do not match the lockfile")
+
+// Lockfile records the provenance of the generated icons
+type Lockfile struct {
+	// Version is the heroicons version the icons were copied from, if known
+	Version string `json:"version,omitempty"`
+	// Archive is the checksum of the downloaded release archive, if the icons were copied
+	// from a downloaded release (see Generator.HeroiconsVersion)
+	Archive string `json:"archive,omitempty"`
+	// Icons maps each icon key (type/name) to the checksum of its content
+	Icons map[string]string `json:"icons"`
+	// Aliases maps the old keys of renamed icons to the keys of the icons they render
+	// (see Generator.AliasRenames). They are carried forward on each generation, so an
+	// alias is kept until its key is used by an icon again or its icon is removed.
+	Aliases map[string]string `json:"aliases,omitempty"`
+}
+
+// ReadLockfile reads a lockfile from the given path
+func ReadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseLockfile(path, data)
+}
+
+// parseLockfile parses the contents of the lockfile at path
+func parseLockfile(path string, data []byte) (*Lockfile, error) {
+	var lock Lockfile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if lock.Icons == nil {
+		lock.Icons = make(map[string]string)
+	}
+
+	return &lock, nil
+}
+
+func (l *Lockfile) write(out WriteFS, name string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return out.WriteFile(name, append(data, '\n'))
+}
+
+// writeLockfile writes the lockfile for the icons in iconPaths and the aliases to the
+// output directory. Aliases are recorded separately from the icons they render.
+func (g *Generator) writeLockfile(iconPaths, aliases map[string]string) error {
+	lock := &Lockfile{
+		Version: g.sourceVersion(),
+		Archive: g.archiveChecksum,
+		Icons:   make(map[string]string, len(iconPaths)),
+	}
+	if len(aliases) > 0 {
+		lock.Aliases = aliases
+	}
+	for key, filename := range iconPaths {
+		if _, ok := aliases[key]; ok {
+			continue
+		}
+		sum, err := checksumFile(g.output(), g.iconsDirName()+"/"+filename)
+		if err != nil {
+			return fmt.Errorf("failed to checksum %s: %w", key, err)
+		}
+		lock.Icons[key] = sum
+	}
+
+	if err := lock.write(g.output(), LockfileName); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
+
+// previousAliases returns the aliases recorded in the existing lockfile, if any
+func (g *Generator) previousAliases() (map[string]string, error) {
+	data, err := fs.ReadFile(g.output(), LockfileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	lock, err := parseLockfile(LockfileName, data)
+	if err != nil {
+		return nil, err
+	}
+	return lock.Aliases, nil
+}
+
+// checkLockfile compares the icons about to be copied with the existing lockfile, if any,
+// and returns ErrLockfileMismatch listing the icons whose content changed since it was
+// written. Icons missing from the source or from the lockfile are not compared.
+func (g *Generator) checkLockfile(icons []IconSet, custom map[string]string) error {
+	data, err := fs.ReadFile(g.output(), LockfileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	lock, err := parseLockfile(LockfileName, data)
+	if err != nil {
+		return err
+	}
+
+	var changes []string
+	if version := g.sourceVersion(); lock.Version != "" && version != "" && version != lock.Version {
+		changes = append(changes, fmt.Sprintf("heroicons version changed from %s to %s", lock.Version, version))
+	}
+
+	checkIcon := func(key string, content []byte, iconType IconType) {
+		if locked, ok := lock.Icons[key]; ok && locked != checksum(g.processIcon(content, iconType)) {
+			changes = append(changes, key)
+		}
+	}
+	for _, icon := range icons {
+		if content, err := g.readIcon(icon); err == nil {
+			checkIcon(icon.Key(), content, icon.Type)
+		}
+	}
+	for key, path := range custom {
+		if content, err := os.ReadFile(path); err == nil {
+			iconType, _, _ := strings.Cut(key, "/")
+			checkIcon(key, content, IconType(iconType))
+		}
+	}
+
+	if len(changes) > 0 {
+		sort.Strings(changes)
+		return fmt.Errorf("%w; set UpdateLockfile to accept the changes:\n%s", ErrLockfileMismatch, strings.Join(changes, "\n"))
+	}
+	return nil
+}
+
+// sourceVersion returns the version from the heroicons package.json, or an empty
+// string if it cannot be determined
+func (g *Generator) sourceVersion() string {
+	if g.Source != nil {
+		return ""
+	}
+
+	data, err := fs.ReadFile(g.sourceFS(), "package.json")
+	if err != nil {
+		return ""
+	}
+
+	var pkg struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return ""
+	}
+	return pkg.Version
+}
+
+// checksumFile returns the checksum of the named file of fsys in the lockfile format
+func checksumFile(fsys fs.FS, name string) (string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+
+	defer func(f fs.File) {
+		_ = f.Close()
+	}(f)
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// checksum returns the checksum of content in the lockfile format
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
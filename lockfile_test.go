@@ -0,0 +1,72 @@
+package heroicons
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLockfile(t *testing.T) {
+	out := &MemFS{}
+	generate := func(source map[string]string, update bool) error {
+		g := newTestGenerator(testSource(source), out, "home")
+		g.WriteLockfile = true
+		g.UpdateLockfile = update
+		return g.Generate()
+	}
+
+	if err := generate(map[string]string{"home": "home"}, false); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := parseLockfile(LockfileName, []byte(readOutput(t, out, LockfileName)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock.Version != "2.2.0" {
+		t.Errorf("version = %q, want 2.2.0", lock.Version)
+	}
+	locked := lock.Icons["outline/home"]
+	if !strings.HasPrefix(locked, "sha256:") {
+		t.Errorf("outline/home checksum = %q", locked)
+	}
+
+	// The same content is accepted
+	if err := generate(map[string]string{"home": "home"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changed content is rejected without writing anything
+	err = generate(map[string]string{"home": "changed"}, false)
+	if !errors.Is(err, ErrLockfileMismatch) || !strings.Contains(err.Error(), "outline/home") {
+		t.Fatalf("got %v, want ErrLockfileMismatch for outline/home", err)
+	}
+	if !strings.Contains(readOutput(t, out, "icons/outline_home.svg"), "<title>home</title>") {
+		t.Error("a rejected generation overwrote the icon")
+	}
+
+	// UpdateLockfile accepts the change
+	if err := generate(map[string]string{"home": "changed"}, true); err != nil {
+		t.Fatal(err)
+	}
+	lock, err = parseLockfile(LockfileName, []byte(readOutput(t, out, LockfileName)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock.Icons["outline/home"] == locked {
+		t.Error("UpdateLockfile did not record the new checksum")
+	}
+}
+
+func TestParseLockfile(t *testing.T) {
+	lock, err := parseLockfile("heroicons.lock", []byte(`{"version": "2.1.0"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock.Icons == nil {
+		t.Error("Icons is nil for a lockfile without icons")
+	}
+
+	if _, err := parseLockfile("heroicons.lock", []byte(`{`)); err == nil || !strings.Contains(err.Error(), "heroicons.lock") {
+		t.Errorf("got %v, want a parse error naming the file", err)
+	}
+}
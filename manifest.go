@@ -0,0 +1,119 @@
+package heroicons
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestFileName is the name of the JSON manifest written to the output directory
+const ManifestFileName = "manifest.json"
+
+// Manifest describes the generated icons for tooling outside Go, such as asset
+// pipelines validating icon references
+type Manifest struct {
+	// Version is the version of the icon source, if known
+	Version string `json:"version,omitempty"`
+	// Icons lists the generated icons, sorted by key
+	Icons []ManifestIcon `json:"icons"`
+}
+
+// ManifestIcon describes a generated icon
+type ManifestIcon struct {
+	Key  string   `json:"key"`
+	Name string   `json:"name"`
+	Type IconType `json:"type"`
+	File string   `json:"file"`
+	Size int64    `json:"size"`
+}
+
+// ReadManifest reads the manifest.json in the given output directory
+func ReadManifest(outputPath string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(outputPath, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// Manifest describes the icons of a generation report, in the format of the manifest.json
+// written with WriteManifest, for tools that consume the generated icons, such as output
+// plugins of the heroicons command. The icons must have been generated, as their sizes
+// are read from the output.
+func (g *Generator) Manifest(report *Report) (*Manifest, error) {
+	files := make(map[string]string, len(report.Icons))
+	for _, key := range report.Icons {
+		fileKey := key
+		if newKey, ok := report.Aliases[key]; ok {
+			fileKey = newKey
+		}
+		iconType, name, _ := strings.Cut(fileKey, "/")
+		file := fmt.Sprintf("%s/%s_%s.svg", g.iconsDirName(), iconType, name)
+		if g.SplitTypes {
+			file = iconType + "/" + file
+		}
+		files[key] = file
+	}
+	return g.manifest(files)
+}
+
+// writeManifest writes manifest.json describing the icons in iconPaths
+func (g *Generator) writeManifest(iconPaths map[string]string) error {
+	files := make(map[string]string, len(iconPaths))
+	for key, filename := range iconPaths {
+		files[key] = g.iconsDirName() + "/" + filename
+	}
+
+	manifest, err := g.manifest(files)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return g.output().WriteFile(ManifestFileName, append(data, '\n'))
+}
+
+// manifest describes the icons in files, which maps icon keys to the names of their files
+// in the output
+func (g *Generator) manifest(files map[string]string) (*Manifest, error) {
+	manifest := &Manifest{
+		Version: g.sourceVersion(),
+		Icons:   make([]ManifestIcon, 0, len(files)),
+	}
+
+	for key, file := range files {
+		icon, err := ParseIconSet(key)
+		if err != nil {
+			return nil, err
+		}
+
+		info, err := fs.Stat(g.output(), file)
+		if err != nil {
+			return nil, err
+		}
+
+		manifest.Icons = append(manifest.Icons, ManifestIcon{
+			Key:  key,
+			Name: icon.Name,
+			Type: icon.Type,
+			File: file,
+			Size: info.Size(),
+		})
+	}
+	sort.Slice(manifest.Icons, func(i, j int) bool {
+		return manifest.Icons[i].Key < manifest.Icons[j].Key
+	})
+	return manifest, nil
+}
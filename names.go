@@ -0,0 +1,111 @@
+package heroicons
+
+import (
+	"maps"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// iconConst is a generated constant for an icon name
+type iconConst struct {
+	Ident string
+	Name  string
+}
+
+// reservedIdents are identifiers already declared by the generated provider
+var reservedIdents = map[string]bool{
+	"Icon":        true,
+	"IconType":    true,
+	"IconOutline": true,
+	"IconSolid":   true,
+	"IconMini":    true,
+	"IconMicro":   true,
+	"IconCustom":  true,
+}
+
+// exportedName converts an icon name such as "arrow-right" to an exported Go identifier
+// fragment such as "ArrowRight"
+func exportedName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// providerIdents are the exported identifiers declared by the generated provider, which
+// icon accessor functions must not use
+var providerIdents = map[string]bool{
+	"AuditARIA": true, "BundlePath": true, "DebugHandler": true, "DebugSource": true,
+	"ExternalReferences": true, "FailOnError": true, "Footprint": true, "Has": true,
+	"Icon": true, "IconCustom": true, "IconMicro": true, "IconMini": true,
+	"IconOutline": true, "IconSolid": true, "IconType": true, "Keys": true,
+	"LoadBundle": true, "MemoryFootprint": true, "PickerHandler": true,
+	"ProfileLabels": true, "RecoverPanics": true, "Refs": true, "Reload": true,
+	"ReloadOnSignal": true, "Render": true, "RenderCounts": true, "RenderIcon": true,
+	"RenderWith": true, "SetAuditARIA": true, "SetDebugSource": true,
+	"SetFailOnError": true, "SetProfileLabels": true, "SetRecoverPanics": true,
+	"SetStrictDeprecations": true, "SetValidateOutput": true,
+	"SpriteIcon": true, "SpriteSheet": true, "StrictDeprecations": true,
+	"ValidateOutput": true, "WriteIcon": true,
+}
+
+// iconNameConsts returns a constant for every icon name in the manifest, sorted by
+// identifier. Names whose identifier would clash with a declaration in the generated
+// provider are skipped with a warning.
+func (g *Generator) iconNameConsts(iconPaths map[string]string) []iconConst {
+	return g.iconIdents(iconPaths, "Icon", reservedIdents, "name constant")
+}
+
+// iconAccessors returns an accessor function identifier for every icon name in the
+// manifest, sorted by identifier. Names whose identifier would clash with a declaration
+// in the generated provider, including the name constants, are skipped with a warning.
+func (g *Generator) iconAccessors(iconPaths map[string]string, nameConsts []iconConst) []iconConst {
+	reserved := maps.Clone(providerIdents)
+	for _, c := range nameConsts {
+		reserved[c.Ident] = true
+	}
+	return g.iconIdents(iconPaths, "", reserved, "accessor")
+}
+
+// iconIdents returns an identifier, made of prefix and the exported icon name, for every
+// icon name in the manifest, skipping reserved, invalid and colliding identifiers
+func (g *Generator) iconIdents(iconPaths map[string]string, prefix string, reserved map[string]bool, kind string) []iconConst {
+	names := make(map[string]string)
+	for key := range iconPaths {
+		_, name, _ := strings.Cut(key, "/")
+		ident := prefix + exportedName(name)
+		if ident == "" || !unicode.IsLetter([]rune(ident)[0]) {
+			g.logf("Skipping %s for icon %q: it does not start with a letter\n", kind, name)
+			continue
+		}
+		if reserved[ident] {
+			g.logf("Skipping %s for icon %q: %s is reserved\n", kind, name, ident)
+			continue
+		}
+		if existing, ok := names[ident]; ok && existing != name {
+			g.logf("Skipping %s for icon %q: %s is already used by %q\n", kind, name, ident, existing)
+			continue
+		}
+		names[ident] = name
+	}
+
+	idents := make([]iconConst, 0, len(names))
+	for ident, name := range names {
+		idents = append(idents, iconConst{Ident: ident, Name: name})
+	}
+	sort.Slice(idents, func(i, j int) bool {
+		return idents[i].Ident < idents[j].Ident
+	})
+	return idents
+}
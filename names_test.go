@@ -0,0 +1,31 @@
+package heroicons
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAccessorsDoNotShadowNameConsts(t *testing.T) {
+	out := &MemFS{}
+	g := newTestGenerator(testSource(map[string]string{"home": "home", "icon-home": "icon home", "keys": "keys"}), out, "home", "icon-home", "keys")
+	g.Accessors = true
+	var log strings.Builder
+	g.Log = &log
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := readOutput(t, out, "provider.go")
+	if n := strings.Count(provider, "\tIconHome "); n != 1 {
+		t.Errorf("IconHome is declared %d times:\n%s", n, provider)
+	}
+	if strings.Contains(provider, "func IconHome(") || strings.Contains(provider, "func Keys(class") {
+		t.Errorf("an accessor shadows a provider declaration:\n%s", provider)
+	}
+	if !containsAll(provider, "func Home(") {
+		t.Errorf("the home accessor is missing:\n%s", provider)
+	}
+	if !containsAll(log.String(), `accessor for icon "icon-home": IconHome is reserved`, `accessor for icon "keys": Keys is reserved`) {
+		t.Errorf("skipped accessors were not logged:\n%s", log.String())
+	}
+}
@@ -0,0 +1,42 @@
+package heroicons
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// checkCaseCollisions returns an error if two icons would be written to file names that
+// differ only in case. On case-insensitive file systems, such as the Windows and macOS
+// defaults, one icon would silently overwrite the other.
+func checkCaseCollisions(icons []IconSet, custom map[string]string) error {
+	keys := make([]string, 0, len(icons)+len(custom))
+	for _, icon := range icons {
+		keys = append(keys, icon.Key())
+	}
+	for key := range custom {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	seen := make(map[string]string, len(keys))
+	var collisions []string
+	for _, key := range keys {
+		folded := strings.ToLower(key)
+		if existing, ok := seen[folded]; ok && existing != key {
+			collisions = append(collisions, fmt.Sprintf("%s and %s", existing, key))
+			continue
+		}
+		seen[folded] = key
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("icons differ only in case and would overwrite each other on case-insensitive file systems:\n%s",
+			strings.Join(collisions, "\n"))
+	}
+	return nil
+}
+
+// validIconName reports whether name can be used in a file name on every platform
+func validIconName(name string) bool {
+	return name != "." && name != ".." && !strings.ContainsAny(name, `/\:`)
+}
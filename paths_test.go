@@ -0,0 +1,94 @@
+package heroicons
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckCaseCollisions(t *testing.T) {
+	tests := []struct {
+		name   string
+		icons  []IconSet
+		custom map[string]string
+		want   string // substring of the error, or empty for none
+	}{
+		{"distinct", []IconSet{{Name: "home", Type: IconOutline}, {Name: "home", Type: IconSolid}}, nil, ""},
+		{"same key twice", []IconSet{{Name: "home", Type: IconOutline}}, map[string]string{"outline/home": "home.svg"}, ""},
+		{"icon names", []IconSet{{Name: "Home", Type: IconOutline}, {Name: "home", Type: IconOutline}}, nil, "outline/Home and outline/home"},
+		{"custom icons", nil, map[string]string{"custom/Logo": "Logo.svg", "custom/logo": "logo.svg"}, "custom/Logo and custom/logo"},
+		{"icon and custom", []IconSet{{Name: "bell", Type: IconOutline}}, map[string]string{"outline/BELL": "BELL.svg"}, "outline/BELL and outline/bell"},
+	}
+	for _, tt := range tests {
+		err := checkCaseCollisions(tt.icons, tt.custom)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%s: got %v, want an error containing %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestParseIconSetSeparators(t *testing.T) {
+	for _, key := range []string{"outline/home", `outline\home`} {
+		icon, err := ParseIconSet(key)
+		if err != nil {
+			t.Errorf("%s: %v", key, err)
+			continue
+		}
+		if icon.Key() != "outline/home" {
+			t.Errorf("%s: key = %s, want outline/home", key, icon.Key())
+		}
+	}
+	for _, key := range []string{"outline", "outline/", `outline\`} {
+		if _, err := ParseIconSet(key); err == nil {
+			t.Errorf("%s: no error", key)
+		}
+	}
+}
+
+func TestValidIconName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"home": true, "arrow-up-right": true, "Logo.v2": true,
+		".": false, "..": false, "a/b": false, `a\b`: false, "c:home": false,
+	} {
+		if got := validIconName(name); got != want {
+			t.Errorf("validIconName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestDirFSLongPaths(t *testing.T) {
+	if root := newDirFS("icons", false).root; !filepath.IsAbs(root) {
+		t.Errorf("root %q is not absolute", root)
+	}
+
+	// Deeper than the 260 character MAX_PATH limit of Windows
+	dir := t.TempDir()
+	for len(dir) < 300 {
+		dir = filepath.Join(dir, strings.Repeat("d", 40))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	out := DirFS(dir)
+	g := &Generator{
+		SourceFS:   testSource(map[string]string{"home": "home"}),
+		OutputPath: dir,
+		Icons:      []IconSet{{Name: "home", Type: IconOutline}},
+		Log:        io.Discard,
+	}
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "icons", "outline_home.svg")); err != nil {
+		t.Error(err)
+	}
+	if err := out.RemoveAll("icons"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,33 @@
+package heroicons
+
+import "sync"
+
+// progress reports copied icons to a Generator's OnProgress callback. Icons are copied
+// concurrently, so calls are serialized and done only ever increases.
+type progress struct {
+	mu         sync.Mutex
+	onProgress func(done, total int, current IconSet)
+	done       int
+	total      int
+}
+
+// newProgress returns a progress reporter for total icons, or nil if OnProgress is not set
+func (g *Generator) newProgress(total int) *progress {
+	if g.OnProgress == nil {
+		return nil
+	}
+	return &progress{onProgress: g.OnProgress, total: total}
+}
+
+// step reports that the given icon has been processed, whether or not it was found
+func (p *progress) step(icon IconSet) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done++
+	p.onProgress(p.done, p.total, icon)
+}
@@ -0,0 +1,152 @@
+package heroicons
+
+import (
+	"strings"
+	"text/template"
+)
+
+// providerTestTemplate is the test written next to the provider with GenerateTests
+const providerTestTemplate = `// Code generated by heroicons generator; DO NOT EDIT.
+
+package {{.PackageName}}
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/patrickward/go-heroicons/core"
+)
+
+// missingTestIcon is the name of an icon that is not in the manifest
+const missingTestIcon = "heroicons-test-missing-icon"
+
+// TestManifestResolves checks that every icon in the manifest can be read from the
+// embedded files, catching broken embeds such as a renamed icons directory
+func TestManifestResolves(t *testing.T) {
+	for _, key := range Keys() {
+		path, _ := iconFile(key)
+		content, err := readIconFile(path)
+		if err != nil {
+			t.Errorf("%s: %v", key, err)
+			continue
+		}
+		if !bytes.Contains(content, []byte("<svg")) {
+			t.Errorf("%s: %s is not an SVG", key, path)
+		}
+	}
+}
+
+// TestMissingIconRenders checks that the missing icon is embedded and rendered in place
+// of an unknown icon
+func TestMissingIconRenders(t *testing.T) {
+	var b strings.Builder
+	_, err := WriteIcon(&b, core.Request{
+		Name:      missingTestIcon,
+		Type:      core.IconOutline,
+		OnMissing: core.MissingFallback,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "<svg") {
+		t.Fatalf("missing icon did not render, got %q", b.String())
+	}
+}
+
+// TestRecoverPanics checks that RecoverPanics turns a panic in a Renderer into an error
+func TestRecoverPanics(t *testing.T) {
+	defer SetRecoverPanics(RecoverPanics)
+	SetRecoverPanics(true)
+
+	panicking := core.RendererFunc(func(io.Writer, []byte, core.Request) error {
+		panic("renderer bug")
+	})
+	err := RenderWith(io.Discard, panicking, core.Request{
+		Name:      missingTestIcon,
+		Type:      core.IconOutline,
+		OnMissing: core.MissingFallback,
+	})
+	if !errors.Is(err, core.ErrPanic) {
+		t.Errorf("got %v, want an error wrapping core.ErrPanic", err)
+	}
+}
+
+// TestFailOnError checks that FailOnError has the generated value and that unknown icons
+// fail to render exactly when it is set
+func TestFailOnError(t *testing.T) {
+	if FailOnError != {{.FailOnError}} {
+		t.Errorf("FailOnError is %v, but the package was generated with %v", FailOnError, {{.FailOnError}})
+	}
+
+	failOnError := failOnError.Get(FailOnError)
+
+	_, err := RenderIcon(missingTestIcon, core.IconOutline, "")
+	if failOnError && err == nil {
+		t.Error("rendering an unknown icon succeeded, but FailOnError is set")
+	}
+	if !failOnError && err != nil {
+		t.Errorf("rendering an unknown icon failed, but FailOnError is not set: %v", err)
+	}
+}
+
+// TestConfigConcurrency changes the configuration with the Set functions while icons are
+// rendered, so go test -race reports unsynchronized access
+func TestConfigConcurrency(t *testing.T) {
+	defer func() {
+		SetFailOnError(FailOnError)
+		SetValidateOutput(ValidateOutput)
+		SetDebugSource(DebugSource)
+		SetAuditARIA(AuditARIA)
+		SetRecoverPanics(RecoverPanics)
+	}()
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				if i == 0 {
+					SetFailOnError(j%2 == 0)
+					SetValidateOutput(j%3 == 0)
+					SetDebugSource(j%5 == 0)
+					SetAuditARIA(func(string, core.IconType) {})
+					SetRecoverPanics(j%2 == 1)
+					continue
+				}
+				for _, key := range Keys() {
+					iconType, name, _ := strings.Cut(key, "/")
+					_, _ = RenderIcon(name, core.IconType(iconType), "w-6")
+				}
+				_, _ = RenderIcon(missingTestIcon, core.IconOutline, "")
+			}
+		}()
+	}
+	wg.Wait()
+}
+`
+
+// providerTestFile returns the name of the test written next to the provider
+func (g *Generator) providerTestFile() string {
+	return strings.TrimSuffix(g.providerFile(), ".go") + "_test.go"
+}
+
+// generateProviderTest writes a test for the generated package that checks every icon in
+// the manifest resolves from the embedded files, the missing icon renders, FailOnError
+// behaves as configured, and the configuration variables can change while rendering
+func (g *Generator) generateProviderTest() error {
+	tmpl, err := template.New("providerTest").Parse(providerTestTemplate)
+	if err != nil {
+		return err
+	}
+
+	return g.executeToFile(tmpl, g.providerTestFile(), map[string]any{
+		"PackageName": g.packageName(),
+		"IconsDir":    g.iconsDirName(),
+		"FailOnError": g.FailOnError,
+	})
+}
@@ -0,0 +1,83 @@
+package heroicons
+
+import (
+	"io/fs"
+	"sort"
+)
+
+// iconChecksums returns the checksum of every icon in the directory dir of fsys, keyed by
+// icon key
+func iconChecksums(fsys fs.FS, dir string) map[string]string {
+	iconPaths, err := existingIconPaths(fsys, dir)
+	if err != nil {
+		return nil
+	}
+
+	sums := make(map[string]string, len(iconPaths))
+	for key, filename := range iconPaths {
+		if sum, err := checksumFile(fsys, dir+"/"+filename); err == nil {
+			sums[key] = sum
+		}
+	}
+	return sums
+}
+
+// detectRenames compares the checksums of the previously generated icons with the newly
+// copied ones in the directory dir of fsys. An icon that is no longer in the manifest but whose content matches a new
+// icon is reported as renamed. The result maps old keys to new keys.
+func detectRenames(previous map[string]string, fsys fs.FS, dir string, iconPaths map[string]string) map[string]string {
+	var removed []string
+	for key := range previous {
+		if _, ok := iconPaths[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	sort.Strings(removed)
+
+	added := make(map[string]string)
+	for key, filename := range iconPaths {
+		if _, ok := previous[key]; ok {
+			continue
+		}
+		if sum, err := checksumFile(fsys, dir+"/"+filename); err == nil {
+			added[sum] = key
+		}
+	}
+
+	renames := make(map[string]string)
+	for _, key := range removed {
+		if newKey, ok := added[previous[key]]; ok {
+			renames[key] = newKey
+		}
+	}
+	if len(renames) == 0 {
+		return nil
+	}
+	return renames
+}
+
+// carryAliases returns the aliases to keep after a generation: the previous aliases, with
+// their target followed when it was renamed in turn, and the renames detected in this
+// generation. Aliases whose key is used by an icon again, or whose icon is no longer in
+// iconPaths, are dropped. iconPaths must not contain aliases.
+func carryAliases(previous, renames, iconPaths map[string]string) map[string]string {
+	aliases := make(map[string]string)
+	for oldKey, target := range previous {
+		if newKey, ok := renames[target]; ok {
+			target = newKey
+		}
+		if _, ok := iconPaths[oldKey]; ok {
+			continue
+		}
+		if _, ok := iconPaths[target]; ok {
+			aliases[oldKey] = target
+		}
+	}
+	for oldKey, newKey := range renames {
+		aliases[oldKey] = newKey
+	}
+	return aliases
+}
@@ -0,0 +1,89 @@
+package heroicons
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestAliasRenamesPersist(t *testing.T) {
+	out := &MemFS{}
+	generate := func(icons map[string]string, names ...string) *Report {
+		t.Helper()
+		g := newTestGenerator(testSource(icons), out, names...)
+		g.AliasRenames = true
+		g.ClearIcons = true
+		report, err := g.GenerateReport()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return report
+	}
+
+	generate(map[string]string{"home": "house"}, "home")
+
+	// Upstream renames home to house
+	report := generate(map[string]string{"house": "house"}, "house")
+	want := map[string]string{"outline/home": "outline/house"}
+	if !maps.Equal(report.Renames, want) {
+		t.Errorf("renames = %v, want %v", report.Renames, want)
+	}
+	if !maps.Equal(report.Aliases, want) {
+		t.Errorf("aliases = %v, want %v", report.Aliases, want)
+	}
+
+	// The alias is kept although no rename is detected any more
+	for run := 3; run <= 4; run++ {
+		report = generate(map[string]string{"house": "house"}, "house")
+		if len(report.Renames) != 0 {
+			t.Errorf("run %d: renames = %v, want none", run, report.Renames)
+		}
+		if !maps.Equal(report.Aliases, want) {
+			t.Errorf("run %d: aliases = %v, want %v", run, report.Aliases, want)
+		}
+		provider := readOutput(t, out, "provider.go")
+		if !containsAll(provider, `"outline/home": "outline_house.svg"`, `"outline/house": "outline_house.svg"`) {
+			t.Errorf("run %d: provider does not map both keys to the icon:\n%s", run, provider)
+		}
+	}
+
+	lock, err := parseLockfile(LockfileName, []byte(readOutput(t, out, LockfileName)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(lock.Aliases, want) {
+		t.Errorf("lockfile aliases = %v, want %v", lock.Aliases, want)
+	}
+	if _, ok := lock.Icons["outline/home"]; ok {
+		t.Error("lockfile lists the alias as an icon")
+	}
+
+	// A second rename follows the chain
+	report = generate(map[string]string{"building": "house"}, "building")
+	want = map[string]string{"outline/home": "outline/building", "outline/house": "outline/building"}
+	if !maps.Equal(report.Aliases, want) {
+		t.Errorf("after second rename: aliases = %v, want %v", report.Aliases, want)
+	}
+
+	// Using an old key for an icon again drops its alias
+	report = generate(map[string]string{"building": "house", "home": "home"}, "building", "home")
+	want = map[string]string{"outline/house": "outline/building"}
+	if !maps.Equal(report.Aliases, want) {
+		t.Errorf("after reusing a key: aliases = %v, want %v", report.Aliases, want)
+	}
+}
+
+func TestCarryAliases(t *testing.T) {
+	iconPaths := map[string]string{"outline/c": "outline_c.svg", "outline/d": "outline_d.svg"}
+	previous := map[string]string{
+		"outline/a": "outline/b", // b was renamed to c
+		"outline/d": "outline/c", // d is an icon again
+		"outline/x": "outline/y", // y was removed
+	}
+	renames := map[string]string{"outline/b": "outline/c"}
+
+	got := carryAliases(previous, renames, iconPaths)
+	want := map[string]string{"outline/a": "outline/c", "outline/b": "outline/c"}
+	if !maps.Equal(got, want) {
+		t.Errorf("carryAliases = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,220 @@
+package heroicons
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// annotationPrefix marks a comment declaring the icons used by Go code
+const annotationPrefix = "//heroicons:use"
+
+// ScanGoAnnotations walks dir for Go source files and collects the icons declared with
+// //heroicons:use comments. Each comment lists one or more icons as type/name:
+//
+//	//heroicons:use outline/envelope solid/check-circle
+//
+// This tracks icons used only from Go code, such as in emails built without templates.
+func ScanGoAnnotations(dir string) ([]IconSet, error) {
+	uses, err := scanGoAnnotationUses(dir)
+	return usedIcons(uses), err
+}
+
+// iconUse is a reference to an icon in a source file
+type iconUse struct {
+	Icon IconSet
+	File string
+	Line int
+}
+
+// usedIcons returns the icons of the given uses
+func usedIcons(uses []iconUse) []IconSet {
+	var icons []IconSet
+	for _, use := range uses {
+		icons = append(icons, use.Icon)
+	}
+	return icons
+}
+
+// scanGoAnnotationUses collects the //heroicons:use declarations in the Go files under dir
+func scanGoAnnotationUses(dir string) ([]iconUse, error) {
+	var uses []iconUse
+	err := walkSource(dir, []string{".go"}, func(path string) error {
+		found, err := scanAnnotations(path)
+		if err != nil {
+			return err
+		}
+		uses = append(uses, found...)
+		return nil
+	})
+	return uses, err
+}
+
+// scanAnnotations collects the icons declared with //heroicons:use comments in a file
+func scanAnnotations(path string) ([]iconUse, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	var uses []iconUse
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		rest, ok := strings.CutPrefix(text, annotationPrefix)
+		if !ok {
+			continue
+		}
+
+		for _, key := range strings.Fields(rest) {
+			icon, err := ParseIconSet(key)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+			uses = append(uses, iconUse{Icon: icon, File: path, Line: line})
+		}
+	}
+	return uses, scanner.Err()
+}
+
+// DefaultTemplateFuncs are the template function names ScanTemplates looks for when none
+// are given
+var DefaultTemplateFuncs = []string{"icon"}
+
+// templateExts are the file extensions scanned for templates
+var templateExts = []string{".html", ".gohtml", ".tmpl", ".gotmpl", ".tpl"}
+
+// templateAction matches a template action, such as {{ icon "home" "outline" }}
+var templateAction = regexp.MustCompile(`(?s){{-?(.*?)-?}}`)
+
+// ScanTemplates walks dir for template files (.html, .gohtml, .tmpl, .gotmpl and .tpl)
+// and collects the icons referenced by calls to the given template functions with
+// literal arguments, such as {{ icon "home" "outline" "w-6 h-6" }}. If funcs is empty,
+// DefaultTemplateFuncs is used. Calls with non-literal arguments are ignored.
+func ScanTemplates(dir string, funcs []string) ([]IconSet, error) {
+	uses, err := scanTemplateUses(dir, funcs)
+	return usedIcons(uses), err
+}
+
+// scanTemplateUses collects the icon calls in the template files under dir
+func scanTemplateUses(dir string, funcs []string) ([]iconUse, error) {
+	if len(funcs) == 0 {
+		funcs = DefaultTemplateFuncs
+	}
+	call := templateCallPattern(funcs)
+
+	var uses []iconUse
+	err := walkSource(dir, templateExts, func(path string) error {
+		found, err := scanTemplate(path, call)
+		if err != nil {
+			return err
+		}
+		uses = append(uses, found...)
+		return nil
+	})
+	return uses, err
+}
+
+// templateCallPattern matches a call to one of funcs with two literal string arguments
+func templateCallPattern(funcs []string) *regexp.Regexp {
+	names := make([]string, len(funcs))
+	for i, f := range funcs {
+		names[i] = regexp.QuoteMeta(f)
+	}
+	literal := "(\"[^\"]*\"|`[^`]*`)"
+	return regexp.MustCompile(`(?:^|[\s(|])(?:` + strings.Join(names, "|") + `)\s+` + literal + `\s+` + literal)
+}
+
+// scanTemplate collects the icons referenced by template calls in a file
+func scanTemplate(path string, call *regexp.Regexp) ([]iconUse, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var uses []iconUse
+	for _, action := range templateAction.FindAllSubmatchIndex(content, -1) {
+		body := content[action[2]:action[3]]
+		for _, m := range call.FindAllSubmatch(body, -1) {
+			name := string(m[1][1 : len(m[1])-1])
+			iconType := string(m[2][1 : len(m[2])-1])
+
+			line := 1 + bytes.Count(content[:action[0]], []byte("\n"))
+			icon, err := ParseIconSet(iconType + "/" + name)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+			uses = append(uses, iconUse{Icon: icon, File: path, Line: line})
+		}
+	}
+	return uses, nil
+}
+
+// walkSource calls fn for every file with one of the given extensions under dir, skipping
+// hidden, vendor and testdata directories
+func walkSource(dir string, exts []string, fn func(path string) error) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !slices.Contains(exts, filepath.Ext(path)) {
+			return nil
+		}
+		return fn(path)
+	})
+}
+
+// resolveIcons returns the configured icons, with wildcards expanded, together with the
+// icons discovered in the annotation and template directories, without duplicates and
+// excluded icons
+func (g *Generator) resolveIcons() ([]IconSet, error) {
+	icons, err := g.expandIcons(g.Icons)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range g.AnnotationDirs {
+		found, err := ScanGoAnnotations(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+		}
+		icons = append(icons, found...)
+	}
+	for _, dir := range g.TemplateDirs {
+		found, err := ScanTemplates(dir, g.TemplateFuncs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+		}
+		icons = append(icons, found...)
+	}
+	return g.excludeIcons(uniqueIcons(icons)), nil
+}
+
+// uniqueIcons removes duplicate icons, keeping the first occurrence
+func uniqueIcons(icons []IconSet) []IconSet {
+	seen := make(map[IconSet]bool, len(icons))
+	unique := icons[:0]
+	for _, icon := range icons {
+		if !seen[icon] {
+			seen[icon] = true
+			unique = append(unique, icon)
+		}
+	}
+	return unique
+}
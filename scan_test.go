@@ -0,0 +1,106 @@
+package heroicons
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestScanGoAnnotations(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"mail/welcome.go": "package mail\n\n//heroicons:use outline/envelope solid/check-circle\nfunc welcome() {}\n",
+		"mail/notes.go":   "package mail\n\n\t//heroicons:use  mini/bell\n// heroicons:use outline/ignored\n",
+		"notes.txt":       "//heroicons:use outline/not-go\n",
+		"vendor/x/x.go":   "//heroicons:use outline/vendored\n",
+		".hidden/x.go":    "//heroicons:use outline/hidden\n",
+		"testdata/x.go":   "//heroicons:use outline/fixture\n",
+	})
+
+	icons, err := ScanGoAnnotations(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, icon := range icons {
+		keys = append(keys, icon.Key())
+	}
+	slices.Sort(keys)
+	if want := []string{"mini/bell", "outline/envelope", "solid/check-circle"}; !slices.Equal(keys, want) {
+		t.Errorf("found %v, want %v", keys, want)
+	}
+}
+
+func TestScanGoAnnotationsInvalidIcon(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.go": "package a\n\n//heroicons:use outline/home envelope\n"})
+
+	_, err := ScanGoAnnotations(dir)
+	if err == nil || !strings.Contains(err.Error(), filepath.Join(dir, "a.go")+":3") {
+		t.Errorf("got %v, want an error with the file and line", err)
+	}
+}
+
+func TestGenerateAnnotationDirs(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.go": "package a\n\n//heroicons:use outline/bell outline/home\n"})
+
+	out := &MemFS{}
+	g := newTestGenerator(testSource(map[string]string{"home": "home", "bell": "bell"}), out, "home")
+	g.AnnotationDirs = []string{dir}
+	report, err := g.GenerateReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"outline/bell", "outline/home"}; !slices.Equal(report.Icons, want) {
+		t.Errorf("generated %v, want %v", report.Icons, want)
+	}
+}
+
+func TestScanTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"layout.html":   `<nav>{{ icon "home" "outline" "w-6 h-6" }}</nav>{{- icon "bell" "solid" -}}`,
+		"page.gohtml":   "{{ if .Admin }}{{ icon `cog` `mini` }}{{ end }}\n{{ icon .Name \"outline\" }}",
+		"list.tmpl":     `{{ range .Items }}{{ (svg "star" "micro" "w-4") }}{{ end }}`,
+		"multi.tpl":     "{{\n  icon \"user\"\n  \"outline\"\n}}",
+		"outside.html":  `icon "not" "outline" is not in an action`,
+		"script.js":     `{{ icon "script" "outline" }}`,
+		"other.gotmpl":  `{{ myicon "prefixed" "outline" }}`,
+		"sub/deep.html": `{{ svg "arrow-up" "outline" | safe }}`,
+	})
+
+	icons, err := ScanTemplates(dir, []string{"icon", "svg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, icon := range icons {
+		keys = append(keys, icon.Key())
+	}
+	slices.Sort(keys)
+	want := []string{"micro/star", "mini/cog", "outline/arrow-up", "outline/home", "outline/user", "solid/bell"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("found %v, want %v", keys, want)
+	}
+
+	// Without funcs, only the default icon func is matched
+	icons, err = ScanTemplates(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(icons) != 4 {
+		t.Errorf("found %v with the default funcs, want the 4 icon calls", icons)
+	}
+}
+
+func TestScanTemplatesInvalidIcon(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.html": "<p>\n\n{{ icon \"home\" \"huge\" }}</p>"})
+
+	_, err := ScanTemplates(dir, nil)
+	if err == nil || !strings.Contains(err.Error(), filepath.Join(dir, "a.html")+":3") {
+		t.Errorf("got %v, want an error with the file and line", err)
+	}
+}
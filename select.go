@@ -0,0 +1,114 @@
+package heroicons
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// IconLister is implemented by icon sources that can list their icons, which is required
+// to expand icon names with wildcards
+type IconLister interface {
+	// List returns the names of every icon of the given type
+	List(iconType IconType) ([]string, error)
+}
+
+// List returns the names of the SVG files in the directory of the given icon type
+func (s layoutSource) List(iconType IconType) ([]string, error) {
+	dir, ok := s.dirs[iconType]
+	if !ok {
+		return nil, fmt.Errorf("%s has no %s icons", s.library, iconType)
+	}
+
+	entries, err := fs.ReadDir(s.fsys, path.Join(s.root, dir))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".svg"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// isPattern reports whether an icon name is a wildcard pattern, such as "arrow-*" or "*"
+func isPattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// matches reports whether the icon matches the given icon or pattern
+func matches(icon, pattern IconSet) bool {
+	if icon.Type != pattern.Type {
+		return false
+	}
+	ok, _ := path.Match(pattern.Name, icon.Name)
+	return ok
+}
+
+// expandIcons replaces icons whose name is a wildcard pattern with every matching icon of
+// the source, sorted by name
+func (g *Generator) expandIcons(icons []IconSet) ([]IconSet, error) {
+	var expanded []IconSet
+	for _, icon := range icons {
+		if !isPattern(icon.Name) {
+			expanded = append(expanded, icon)
+			continue
+		}
+
+		lister, ok := g.source().(IconLister)
+		if !ok {
+			return nil, fmt.Errorf("icon %s/%s: the icon source cannot list icons for wildcards", icon.Type, icon.Name)
+		}
+		names, err := lister.List(icon.Type)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s icons: %w", icon.Type, err)
+		}
+
+		sort.Strings(names)
+		for _, name := range names {
+			if match := (IconSet{Name: name, Type: icon.Type}); matches(match, icon) {
+				expanded = append(expanded, match)
+			}
+		}
+	}
+	return expanded, nil
+}
+
+// excludeIcons removes the icons matching any of the Exclude patterns
+func (g *Generator) excludeIcons(icons []IconSet) []IconSet {
+	if len(g.Exclude) == 0 {
+		return icons
+	}
+
+	included := icons[:0]
+	for _, icon := range icons {
+		excluded := false
+		for _, pattern := range g.Exclude {
+			if matches(icon, pattern) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			included = append(included, icon)
+		}
+	}
+	return included
+}
+
+// validatePatterns checks the wildcard patterns of the given icons
+func validatePatterns(icons []IconSet, kind string) []error {
+	var errs []error
+	for _, icon := range icons {
+		if _, err := path.Match(icon.Name, ""); errors.Is(err, path.ErrBadPattern) {
+			errs = append(errs, fmt.Errorf("%s %s/%s is not a valid pattern", kind, icon.Type, icon.Name))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,188 @@
+package heroicons
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// IconSize is the size of a single embedded icon
+type IconSize struct {
+	// Key is the icon key (type/name)
+	Key string `json:"key"`
+	// Type is the icon type
+	Type IconType `json:"type"`
+	// Bytes is the size of the embedded SVG content
+	Bytes int64 `json:"bytes"`
+	// Package is the type package embedding the icon, such as outline, for output
+	// generated with SplitTypes
+	Package string `json:"package,omitempty"`
+}
+
+// SizeReport describes how much each icon and icon type contributes to the size of a
+// generated package
+type SizeReport struct {
+	// Icons lists the embedded icons, largest first
+	Icons []IconSize `json:"icons"`
+	// Types maps each icon type to the total size of its icons
+	Types map[IconType]int64 `json:"types"`
+	// Total is the total size of all embedded icons
+	Total int64 `json:"total"`
+	// Packages maps each type package to the total size of its icons, for output
+	// generated with SplitTypes
+	Packages map[string]int64 `json:"packages,omitempty"`
+}
+
+// AnalyzeSize reports the size of the icons embedded by the generated package in
+// outputPath, per icon and per type, to guide pruning decisions. It expects the default
+// icons directory; use Generator.AnalyzeSize when IconsDir or SplitTypes is set.
+func AnalyzeSize(outputPath string) (*SizeReport, error) {
+	return analyzeSize(os.DirFS(outputPath), iconsDir)
+}
+
+// AnalyzeSize reports the size of the icons embedded by the generator's package, like
+// the AnalyzeSize function. With SplitTypes, it reports the icons of every type package
+// along with the total of each package.
+func (g *Generator) AnalyzeSize() (*SizeReport, error) {
+	if g.SplitTypes {
+		return g.analyzeSplitSize()
+	}
+	return analyzeSize(g.output(), g.iconsDirName())
+}
+
+// analyzeSplitSize combines the size reports of the type packages generated with
+// SplitTypes
+func (g *Generator) analyzeSplitSize() (*SizeReport, error) {
+	out := g.output()
+	report := &SizeReport{Types: make(map[IconType]int64), Packages: make(map[string]int64)}
+	for _, iconType := range iconTypes {
+		pkg := string(iconType)
+		if _, err := fs.Stat(out, pkg); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		typeReport, err := analyzeSize(subFS(out, pkg), g.iconsDirName())
+		if err != nil {
+			return nil, fmt.Errorf("package %s: %w", pkg, err)
+		}
+		for _, icon := range typeReport.Icons {
+			icon.Package = pkg
+			report.Icons = append(report.Icons, icon)
+		}
+		for iconType, size := range typeReport.Types {
+			report.Types[iconType] += size
+		}
+		report.Total += typeReport.Total
+		report.Packages[pkg] = typeReport.Total
+	}
+	if len(report.Packages) == 0 {
+		return nil, fmt.Errorf("no type packages found in %s", g.OutputPath)
+	}
+
+	sortIconSizes(report.Icons)
+	return report, nil
+}
+
+// analyzeSize reports the size of the icons in the directory dir of fsys and the custom
+// icons
+func analyzeSize(fsys fs.FS, dir string) (*SizeReport, error) {
+	iconPaths, err := existingIconPaths(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read icons directory: %w", err)
+	}
+
+	report := &SizeReport{Types: make(map[IconType]int64)}
+	add := func(key, name string) error {
+		info, err := fs.Stat(fsys, name)
+		if err != nil {
+			return err
+		}
+		iconType, _, _ := strings.Cut(key, "/")
+		report.Icons = append(report.Icons, IconSize{Key: key, Type: IconType(iconType), Bytes: info.Size()})
+		report.Types[IconType(iconType)] += info.Size()
+		report.Total += info.Size()
+		return nil
+	}
+
+	for key, filename := range iconPaths {
+		if err := add(key, dir+"/"+filename); err != nil {
+			return nil, err
+		}
+	}
+
+	customNames, _ := fs.Glob(fsys, customIconsDir+"/*.svg")
+	for _, name := range customNames {
+		key := fmt.Sprintf("%s/%s", IconCustom, strings.TrimSuffix(path.Base(name), ".svg"))
+		if err := add(key, name); err != nil {
+			return nil, err
+		}
+	}
+
+	sortIconSizes(report.Icons)
+	return report, nil
+}
+
+// sortIconSizes sorts icons largest first, then by key and package
+func sortIconSizes(icons []IconSize) {
+	sort.Slice(icons, func(i, j int) bool {
+		if icons[i].Bytes != icons[j].Bytes {
+			return icons[i].Bytes > icons[j].Bytes
+		}
+		if icons[i].Key != icons[j].Key {
+			return icons[i].Key < icons[j].Key
+		}
+		return icons[i].Package < icons[j].Package
+	})
+}
+
+// Write writes the report as a human-readable table
+func (r *SizeReport) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	types := make([]string, 0, len(r.Types))
+	for iconType := range r.Types {
+		types = append(types, string(iconType))
+	}
+	sort.Strings(types)
+
+	if len(r.Packages) > 0 {
+		packages := make([]string, 0, len(r.Packages))
+		for pkg := range r.Packages {
+			packages = append(packages, pkg)
+		}
+		sort.Strings(packages)
+
+		fmt.Fprintln(tw, "PACKAGE\tBYTES\t")
+		for _, pkg := range packages {
+			fmt.Fprintf(tw, "%s\t%d\t\n", pkg, r.Packages[pkg])
+		}
+		fmt.Fprintln(tw)
+	}
+
+	fmt.Fprintln(tw, "TYPE\tBYTES\t")
+	for _, iconType := range types {
+		fmt.Fprintf(tw, "%s\t%d\t\n", iconType, r.Types[IconType(iconType)])
+	}
+	fmt.Fprintf(tw, "total\t%d\t\n\n", r.Total)
+
+	if len(r.Packages) > 0 {
+		fmt.Fprintln(tw, "ICON\tPACKAGE\tBYTES\t")
+		for _, icon := range r.Icons {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t\n", icon.Key, icon.Package, icon.Bytes)
+		}
+		return tw.Flush()
+	}
+
+	fmt.Fprintln(tw, "ICON\tBYTES\t")
+	for _, icon := range r.Icons {
+		fmt.Fprintf(tw, "%s\t%d\t\n", icon.Key, icon.Bytes)
+	}
+
+	return tw.Flush()
+}
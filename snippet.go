@@ -0,0 +1,55 @@
+package heroicons
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// SnippetFormat identifies the kind of code a snippet is written in
+type SnippetFormat string
+
+const (
+	SnippetTemplate   SnippetFormat = "html/template" // html/template action using the "icon" func
+	SnippetTempl      SnippetFormat = "templ"         // templ component expression
+	SnippetGomponents SnippetFormat = "gomponents"    // gomponents node
+	SnippetGo         SnippetFormat = "go"            // plain Go call
+)
+
+// Snippet is a ready-to-paste usage example for an icon
+type Snippet struct {
+	Format SnippetFormat `json:"format"`
+	Code   string        `json:"code"`
+}
+
+// Snippets returns usage examples for rendering the icon with the given classes, in each
+// supported format. pkg is the name of the generated package (for example, "icons"). The
+// html/template snippet assumes the package's Icon func is registered as "icon".
+func Snippets(pkg string, icon IconSet, class string) []Snippet {
+	name := strconv.Quote(icon.Name)
+	classArg := strconv.Quote(class)
+	call := fmt.Sprintf("%s.Icon(%s, %s, %s)", pkg, name, iconTypeExpr(icon.Type), classArg)
+
+	return []Snippet{
+		{Format: SnippetTemplate, Code: fmt.Sprintf("{{ icon %s %s %s }}", name, strconv.Quote(string(icon.Type)), classArg)},
+		{Format: SnippetTempl, Code: fmt.Sprintf("@templ.Raw(%s)", call)},
+		{Format: SnippetGomponents, Code: fmt.Sprintf("g.Raw(string(%s))", call)},
+		{Format: SnippetGo, Code: fmt.Sprintf("svg, err := %s.RenderIcon(%s, %s, %s)", pkg, name, iconTypeExpr(icon.Type), classArg)},
+	}
+}
+
+// iconTypeExpr returns the Go expression for the icon type
+func iconTypeExpr(t IconType) string {
+	switch t {
+	case IconOutline:
+		return "core.IconOutline"
+	case IconSolid:
+		return "core.IconSolid"
+	case IconMini:
+		return "core.IconMini"
+	case IconMicro:
+		return "core.IconMicro"
+	case IconCustom:
+		return "core.IconCustom"
+	}
+	return strconv.Quote(string(t))
+}
@@ -0,0 +1,153 @@
+package heroicons
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// IconSource resolves icons to their SVG content, so libraries other than Heroicons can
+// be used with the same manifest and provider. Icons are copied concurrently, so Open
+// must be safe for concurrent use.
+type IconSource interface {
+	// Open returns the SVG content of the given icon
+	Open(icon IconSet) (io.ReadCloser, error)
+}
+
+// layoutSource is an IconSource reading icons from a directory per icon type
+type layoutSource struct {
+	library string
+	fsys    fs.FS
+	// root is the directory of fsys holding the icon type directories
+	root string
+	dirs map[IconType]string
+}
+
+// Open returns the SVG file of the given icon
+func (s layoutSource) Open(icon IconSet) (io.ReadCloser, error) {
+	dir, ok := s.dirs[icon.Type]
+	if !ok {
+		return nil, fmt.Errorf("%s has no %s icons", s.library, icon.Type)
+	}
+	return s.fsys.Open(path.Join(s.root, dir, icon.Name+".svg"))
+}
+
+// HeroiconsSource returns an IconSource for a clone of the heroicons repository (or an
+// extracted release) at path, or for the heroicons npm package, such as
+// node_modules/heroicons
+func HeroiconsSource(path string) IconSource {
+	return HeroiconsFS(os.DirFS(path))
+}
+
+// HeroiconsFS returns an IconSource for a copy of the heroicons repository or npm package
+// in fsys, such as an embedded copy or a zip archive. The optimized directory of the
+// repository, or the size directories of the package, must be at the root of fsys; use
+// fs.Sub for a copy in a subdirectory.
+func HeroiconsFS(fsys fs.FS) IconSource {
+	root := "optimized"
+	if _, err := fs.Stat(fsys, root); err != nil && isNPMPackage(fsys) {
+		// The npm package holds the optimized icons at its root
+		root = "."
+	}
+	return layoutSource{
+		library: "heroicons",
+		fsys:    fsys,
+		root:    root,
+		dirs:    heroiconsDirs,
+	}
+}
+
+// isNPMPackage reports whether fsys holds the heroicons npm package layout
+func isNPMPackage(fsys fs.FS) bool {
+	info, err := fs.Stat(fsys, heroiconsDirs[IconOutline])
+	return err == nil && info.IsDir()
+}
+
+// heroiconsDirs are the directories of each icon type in the heroicons optimized folder
+var heroiconsDirs = map[IconType]string{
+	IconOutline: "24/outline",
+	IconSolid:   "24/solid",
+	IconMini:    "20/solid",
+	IconMicro:   "16/solid",
+	IconCustom:  "custom",
+}
+
+// LucideSource returns an IconSource for a clone of the Lucide repository (or the
+// lucide-static package) at path. Lucide icons are provided as outline icons.
+func LucideSource(path string) IconSource {
+	return layoutSource{
+		library: "lucide",
+		fsys:    os.DirFS(path),
+		root:    ".",
+		dirs:    map[IconType]string{IconOutline: "icons"},
+	}
+}
+
+// FeatherSource returns an IconSource for a clone of the Feather repository at path.
+// Feather icons are provided as outline icons.
+func FeatherSource(path string) IconSource {
+	return layoutSource{
+		library: "feather",
+		fsys:    os.DirFS(path),
+		root:    ".",
+		dirs:    map[IconType]string{IconOutline: "icons"},
+	}
+}
+
+// TablerSource returns an IconSource for a clone of the Tabler Icons repository (or the
+// @tabler/icons package) at path. Outline icons are provided as outline icons and filled
+// icons as solid icons.
+func TablerSource(path string) IconSource {
+	return layoutSource{
+		library: "tabler",
+		fsys:    os.DirFS(path),
+		root:    ".",
+		dirs: map[IconType]string{
+			IconOutline: "icons/outline",
+			IconSolid:   "icons/filled",
+		},
+	}
+}
+
+// librarySources maps the library names accepted in config files to their sources
+var librarySources = map[string]func(path string) IconSource{
+	"heroicons": HeroiconsSource,
+	"lucide":    LucideSource,
+	"feather":   FeatherSource,
+	"tabler":    TablerSource,
+}
+
+// source returns the IconSource icons are copied from
+func (g *Generator) source() IconSource {
+	if g.Source != nil {
+		return g.Source
+	}
+	return HeroiconsFS(g.sourceFS())
+}
+
+// sourceFS returns the heroicons repository icons are copied from when Source is not set
+func (g *Generator) sourceFS() fs.FS {
+	if g.SourceFS != nil {
+		return g.SourceFS
+	}
+	return os.DirFS(g.sourceDir())
+}
+
+// sourceAt returns a source of the same library as Source for the copy at path, to read
+// other versions of the library
+func (g *Generator) sourceAt(path string) (IconSource, error) {
+	if g.Source == nil {
+		return HeroiconsSource(path), nil
+	}
+	if source, ok := g.Source.(layoutSource); ok {
+		return librarySources[source.library](path), nil
+	}
+	return nil, fmt.Errorf("cannot read another version of the configured %T Source from a path: use DiffSources", g.Source)
+}
+
+// readIcon returns the SVG content of the given icon from the source
+func (g *Generator) readIcon(icon IconSet) ([]byte, error) {
+	return readSource(g.source(), icon)
+}
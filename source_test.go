@@ -0,0 +1,93 @@
+package heroicons
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLibrarySources(t *testing.T) {
+	tests := []struct {
+		name   string
+		source func(path string) IconSource
+		files  map[string]string
+		icons  map[IconSet]string
+	}{
+		{
+			"lucide", LucideSource,
+			map[string]string{"icons/house.svg": "lucide house"},
+			map[IconSet]string{{Name: "house", Type: IconOutline}: "lucide house"},
+		},
+		{
+			"feather", FeatherSource,
+			map[string]string{"icons/home.svg": "feather home"},
+			map[IconSet]string{{Name: "home", Type: IconOutline}: "feather home"},
+		},
+		{
+			"tabler", TablerSource,
+			map[string]string{"icons/outline/home.svg": "tabler home", "icons/filled/home.svg": "tabler filled home"},
+			map[IconSet]string{{Name: "home", Type: IconOutline}: "tabler home", {Name: "home", Type: IconSolid}: "tabler filled home"},
+		},
+		{
+			"heroicons", HeroiconsSource,
+			map[string]string{"optimized/24/outline/home.svg": "outline home", "optimized/16/solid/home.svg": "micro home"},
+			map[IconSet]string{{Name: "home", Type: IconOutline}: "outline home", {Name: "home", Type: IconMicro}: "micro home"},
+		},
+		{
+			"heroicons npm", HeroiconsSource,
+			map[string]string{"package.json": "{}", "24/outline/home.svg": "npm home", "20/solid/home.svg": "npm mini home"},
+			map[IconSet]string{{Name: "home", Type: IconOutline}: "npm home", {Name: "home", Type: IconMini}: "npm mini home"},
+		},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		writeTree(t, dir, tt.files)
+		source := tt.source(dir)
+		for icon, want := range tt.icons {
+			content, err := readSource(source, icon)
+			if err != nil || string(content) != want {
+				t.Errorf("%s: %s = %q, %v, want %q", tt.name, icon.Key(), content, err, want)
+			}
+		}
+	}
+
+	_, err := LucideSource(t.TempDir()).Open(IconSet{Name: "home", Type: IconSolid})
+	if err == nil || !strings.Contains(err.Error(), "lucide has no solid icons") {
+		t.Errorf("got %v, want an error for a type the library does not provide", err)
+	}
+}
+
+func TestGenerateFromSource(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"icons/house.svg": string(testSVG("lucide house").Data)})
+
+	out := &MemFS{}
+	g := newTestGenerator(nil, out, "house", "nope")
+	g.Source = LucideSource(dir)
+	report, err := g.GenerateReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Missing) != 1 || report.Missing[0] != "outline/nope" {
+		t.Errorf("Missing = %v, want outline/nope", report.Missing)
+	}
+	if !strings.Contains(readOutput(t, out, "icons/outline_house.svg"), "lucide house") {
+		t.Error("the icon was not read from the source")
+	}
+
+	// A config file selects the library, relative to the config directory
+	path := writeConfig(t, dir, `{"source": {"library": "lucide", "path": "."}, "icons": ["outline/house"]}`)
+	loaded := &Generator{}
+	if err := loaded.LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	r, err := loaded.Source.Open(IconSet{Name: "house", Type: IconOutline})
+	if err != nil {
+		t.Fatalf("source from %s: %v", filepath.Base(path), err)
+	}
+	defer func() { _ = r.Close() }()
+	if content, _ := io.ReadAll(r); !strings.Contains(string(content), "lucide house") {
+		t.Errorf("config source read %q", content)
+	}
+}
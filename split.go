@@ -0,0 +1,134 @@
+package heroicons
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sort"
+)
+
+// generateSplit generates a separate package for each icon type into a subdirectory of
+// OutputPath named after the type, such as icons/outline, each embedding only its own
+// icons. Each package is generated as if by a generator for that type alone, and the
+// reports are combined.
+func (g *Generator) generateSplit(ctx context.Context) (*Report, error) {
+	icons, err := g.resolveIcons()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := g.customIcons(icons); err != nil {
+		return nil, err
+	}
+
+	byType := make(map[IconType][]IconSet)
+	for _, icon := range icons {
+		byType[icon.Type] = append(byType[icon.Type], icon)
+	}
+	customDirs := make(map[IconType][]CustomSource)
+	for _, source := range g.CustomDirs {
+		customDirs[source.iconType()] = append(customDirs[source.iconType()], source)
+	}
+
+	report := &Report{
+		OutputPath: g.OutputPath,
+		Icons:      []string{},
+		Renames:    make(map[string]string),
+	}
+	for _, iconType := range iconTypes {
+		if len(byType[iconType]) == 0 && len(customDirs[iconType]) == 0 {
+			continue
+		}
+
+		typed := g.typeGenerator(iconType, byType[iconType], customDirs[iconType])
+		typeReport, err := typed.GenerateReportContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Icons = append(report.Icons, typeReport.Icons...)
+		report.Missing = append(report.Missing, typeReport.Missing...)
+		report.Conflicts = append(report.Conflicts, typeReport.Conflicts...)
+		report.Created = append(report.Created, typeReport.Created...)
+		report.Overwritten = append(report.Overwritten, typeReport.Overwritten...)
+		report.Removed = append(report.Removed, typeReport.Removed...)
+		for oldKey, newKey := range typeReport.Renames {
+			report.Renames[oldKey] = newKey
+		}
+		for oldKey, target := range typeReport.Aliases {
+			if report.Aliases == nil {
+				report.Aliases = make(map[string]string)
+			}
+			report.Aliases[oldKey] = target
+		}
+	}
+	sort.Strings(report.Icons)
+
+	if g.DryRun {
+		return report, g.missingError(report.Missing)
+	}
+	if err := g.runHooks(report); err != nil {
+		return report, err
+	}
+	return report, g.missingError(report.Missing)
+}
+
+// generateSplitIcons adds icons to the packages of their types, as GenerateIcons does
+// for a single package
+func (g *Generator) generateSplitIcons(icons []IconSet) error {
+	byType := make(map[IconType][]IconSet)
+	for _, icon := range icons {
+		byType[icon.Type] = append(byType[icon.Type], icon)
+	}
+	customDirs := make(map[IconType][]CustomSource)
+	for _, source := range g.CustomDirs {
+		customDirs[source.iconType()] = append(customDirs[source.iconType()], source)
+	}
+
+	var errs []error
+	for _, iconType := range iconTypes {
+		if len(byType[iconType]) == 0 {
+			continue
+		}
+		typed := g.typeGenerator(iconType, byType[iconType], customDirs[iconType])
+		typed.FailOnMissing = g.FailOnMissing
+		if err := typed.GenerateIcons(byType[iconType]...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// typeGenerator returns a generator for the package of a single icon type. Icons are
+// already resolved, so scanning and exclusions are not repeated, and hooks run once for
+// the combined report.
+func (g *Generator) typeGenerator(iconType IconType, icons []IconSet, customDirs []CustomSource) *Generator {
+	typed := *g
+	typed.SplitTypes = false
+	typed.OutputPath = filepath.Join(g.OutputPath, string(iconType))
+	if g.Output != nil {
+		typed.Output = subFS(g.Output, string(iconType))
+	}
+	typed.PackageName = string(iconType)
+	typed.Icons = icons
+	typed.Exclude = nil
+	typed.AnnotationDirs = nil
+	typed.TemplateDirs = nil
+	typed.CustomDirs = customDirs
+	typed.PostGenerate = nil
+	typed.Webhook = ""
+	typed.FailOnMissing = false
+	return &typed
+}
+
+// validateSplit checks the options that cannot be combined with SplitTypes
+func (g *Generator) validateSplit() []error {
+	if !g.SplitTypes {
+		return nil
+	}
+
+	var errs []error
+	if g.TypedFuncs {
+		errs = append(errs, errors.New("SplitTypes and TypedFuncs cannot be used together: both write a package per icon type"))
+	}
+	return errs
+}
@@ -0,0 +1,80 @@
+package heroicons
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateIconsSplitTypes(t *testing.T) {
+	source := testSource(map[string]string{"home": "home", "bell": "bell"})
+	source["optimized/24/solid/bell.svg"] = testSVG("solid bell")
+
+	out := &MemFS{}
+	g := newTestGenerator(source, out, "home")
+	g.SplitTypes = true
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.GenerateIcons(IconSet{Name: "bell", Type: IconOutline}, IconSet{Name: "bell", Type: IconSolid}); err != nil {
+		t.Fatal(err)
+	}
+
+	outline := readOutput(t, out, "outline/provider.go")
+	if !containsAll(outline, "package outline", `"outline/home"`, `"outline/bell"`) {
+		t.Errorf("outline provider does not list both icons:\n%s", outline)
+	}
+	solid := readOutput(t, out, "solid/provider.go")
+	if !containsAll(solid, "package solid", `"solid/bell"`) || strings.Contains(solid, "outline/") {
+		t.Errorf("solid provider does not list only the solid icon:\n%s", solid)
+	}
+	if _, err := out.Open("provider.go"); err == nil {
+		t.Error("GenerateIcons wrote a provider outside the type packages")
+	}
+}
+
+func TestAnalyzeSizeSplitTypes(t *testing.T) {
+	source := testSource(map[string]string{"home": "home", "bell": "bell"})
+	source["optimized/24/solid/bell.svg"] = testSVG("solid bell")
+
+	out := &MemFS{}
+	g := newTestGenerator(source, out, "home", "bell")
+	g.Icons = append(g.Icons, IconSet{Name: "bell", Type: IconSolid})
+	g.SplitTypes = true
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := g.AnalyzeSize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Packages) != 2 {
+		t.Errorf("Packages = %v, want outline and solid", report.Packages)
+	}
+	perPackage := make(map[string]int64)
+	var sum int64
+	for _, icon := range report.Icons {
+		if icon.Type != IconCustom && icon.Package != string(icon.Type) {
+			t.Errorf("%s reported in package %q", icon.Key, icon.Package)
+		}
+		perPackage[icon.Package] += icon.Bytes
+		sum += icon.Bytes
+	}
+	for pkg, size := range report.Packages {
+		if perPackage[pkg] != size {
+			t.Errorf("package %s totals %d, want the sum of its icons %d", pkg, size, perPackage[pkg])
+		}
+	}
+	if report.Total != sum {
+		t.Errorf("Total = %d, want %d", report.Total, sum)
+	}
+
+	var table strings.Builder
+	if err := report.Write(&table); err != nil {
+		t.Fatal(err)
+	}
+	if !containsAll(table.String(), "PACKAGE", "outline", "solid/bell") {
+		t.Errorf("table does not list the packages:\n%s", table.String())
+	}
+}
@@ -0,0 +1,66 @@
+package heroicons
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SpriteFileName is the name of the sprite sheet written to the output directory
+const SpriteFileName = "sprite.svg"
+
+var (
+	svgRootPattern  = regexp.MustCompile(`(?s)<svg\b([^>]*)>(.*)</svg>`)
+	svgAttrPattern  = regexp.MustCompile(`([\w:-]+)\s*=\s*"([^"]*)"`)
+	symbolSkipAttrs = map[string]bool{
+		"xmlns": true, "width": true, "height": true, "class": true,
+		"aria-hidden": true, "data-slot": true, "id": true,
+	}
+)
+
+// writeSprite writes a sprite sheet with a <symbol> for each icon in the manifest and
+// returns the viewBox of each icon, keyed by icon key (type/name)
+func (g *Generator) writeSprite(iconPaths map[string]string) (map[string]string, error) {
+	keys := make([]string, 0, len(iconPaths))
+	for key := range iconPaths {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sprite strings.Builder
+	sprite.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" aria-hidden="true" style="display:none">` + "\n")
+
+	viewBoxes := make(map[string]string, len(keys))
+	for _, key := range keys {
+		content, err := fs.ReadFile(g.output(), g.iconsDirName()+"/"+iconPaths[key])
+		if err != nil {
+			return nil, err
+		}
+
+		match := svgRootPattern.FindSubmatch(content)
+		if match == nil {
+			return nil, fmt.Errorf("icon %s is not an SVG document", key)
+		}
+
+		iconType, name, _ := strings.Cut(key, "/")
+		fmt.Fprintf(&sprite, `<symbol id="%s"`, SpriteID(name, IconType(iconType)))
+		for _, attr := range svgAttrPattern.FindAllSubmatch(match[1], -1) {
+			attrName := string(attr[1])
+			if attrName == "viewBox" {
+				viewBoxes[key] = string(attr[2])
+			}
+			if !symbolSkipAttrs[attrName] {
+				fmt.Fprintf(&sprite, ` %s="%s"`, attrName, attr[2])
+			}
+		}
+		fmt.Fprintf(&sprite, ">%s</symbol>\n", strings.TrimSpace(string(match[2])))
+	}
+	sprite.WriteString("</svg>\n")
+
+	if err := g.output().WriteFile(SpriteFileName, []byte(sprite.String())); err != nil {
+		return nil, err
+	}
+	return viewBoxes, nil
+}
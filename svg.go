@@ -0,0 +1,57 @@
+package heroicons
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// svgRootTagPattern matches the opening tag of the root svg element
+var svgRootTagPattern = regexp.MustCompile(`<svg\b[^>]*>`)
+
+// processIcon applies the configured transformations to the SVG content of an icon
+func (g *Generator) processIcon(svg []byte, iconType IconType) []byte {
+	if g.Normalize {
+		svg = normalizeIcon(svg, iconType)
+	}
+	for _, attr := range g.StripAttributes {
+		svg = stripAttribute(svg, attr)
+	}
+	return svg
+}
+
+// stripAttribute removes every occurrence of the named attribute from the SVG content
+func stripAttribute(svg []byte, name string) []byte {
+	re := regexp.MustCompile(`\s+` + regexp.QuoteMeta(name) + `\s*=\s*("[^"]*"|'[^']*')`)
+	return re.ReplaceAll(svg, nil)
+}
+
+// normalizeIcon removes the fixed width and height of the root svg element and makes the
+// icon use the current color: outline icons are stroked and solid icons filled with
+// currentColor. Custom icons are only resized, as their style is unknown.
+func normalizeIcon(svg []byte, iconType IconType) []byte {
+	loc := svgRootTagPattern.FindIndex(svg)
+	if loc == nil {
+		return svg
+	}
+
+	root := svg[loc[0]:loc[1]]
+	root = stripAttribute(root, "width")
+	root = stripAttribute(root, "height")
+	switch iconType {
+	case IconOutline:
+		root = setAttribute(root, "stroke", "currentColor")
+	case IconSolid, IconMini, IconMicro:
+		root = setAttribute(root, "fill", "currentColor")
+	}
+
+	result := append([]byte(nil), svg[:loc[0]]...)
+	result = append(result, root...)
+	return append(result, svg[loc[1]:]...)
+}
+
+// setAttribute sets the named attribute of the given opening tag, replacing its value if
+// it is already present
+func setAttribute(tag []byte, name, value string) []byte {
+	tag = stripAttribute(tag, name)
+	return append([]byte(fmt.Sprintf(`<svg %s="%s"`, name, value)), tag[len("<svg"):]...)
+}
@@ -0,0 +1,54 @@
+// Package turbo renders icons as Turbo Stream actions, for server-driven updates of icons
+// (such as a notification bell changing state) with Hotwire.
+package turbo
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/patrickward/go-heroicons/core"
+)
+
+// ContentType is the MIME type of Turbo Stream responses
+const ContentType = "text/vnd.turbo-stream.html"
+
+// Stream wraps content in a turbo-stream element with the given action and target ID
+func Stream(action, target string, content template.HTML) template.HTML {
+	return template.HTML(fmt.Sprintf(`<turbo-stream action="%s" target="%s"><template>%s</template></turbo-stream>`,
+		template.HTMLEscapeString(action), template.HTMLEscapeString(target), content))
+}
+
+// Replace returns a stream action that replaces the element with the given target ID with
+// the icon. The icon is given the target ID, so later updates can replace it again.
+func Replace(render core.RenderFunc, target, name string, iconType core.IconType, class string) (template.HTML, error) {
+	svg, err := render(name, iconType, class)
+	if err != nil {
+		return "", err
+	}
+
+	svg = template.HTML(strings.Replace(string(svg), "<svg ", fmt.Sprintf(`<svg id="%s" `, template.HTMLEscapeString(target)), 1))
+	return Stream("replace", target, svg), nil
+}
+
+// Update returns a stream action that replaces the contents of the element with the given
+// target ID with the icon
+func Update(render core.RenderFunc, target, name string, iconType core.IconType, class string) (template.HTML, error) {
+	svg, err := render(name, iconType, class)
+	if err != nil {
+		return "", err
+	}
+	return Stream("update", target, svg), nil
+}
+
+// Write writes the given stream actions as a Turbo Stream response
+func Write(w http.ResponseWriter, streams ...template.HTML) error {
+	w.Header().Set("Content-Type", ContentType)
+	for _, stream := range streams {
+		if _, err := fmt.Fprintln(w, stream); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package turbo
+
+import (
+	"errors"
+	"html/template"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/patrickward/go-heroicons/core"
+)
+
+// testRender renders a minimal SVG naming the icon, failing for the icon named "nope"
+func testRender(name string, iconType core.IconType, class string) (template.HTML, error) {
+	if name == "nope" {
+		return "", errors.New("icon not found")
+	}
+	return template.HTML(`<svg class="` + class + `" data-icon="` + string(iconType) + "/" + name + `"></svg>`), nil
+}
+
+func TestReplace(t *testing.T) {
+	stream, err := Replace(testRender, `bell"><x`, "bell", core.IconSolid, "w-6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<turbo-stream action="replace" target="bell&#34;&gt;&lt;x"><template><svg id="bell&#34;&gt;&lt;x" class="w-6" data-icon="solid/bell"></svg></template></turbo-stream>`
+	if string(stream) != want {
+		t.Errorf("got  %s\nwant %s", stream, want)
+	}
+
+	if _, err := Replace(testRender, "bell", "nope", core.IconSolid, ""); err == nil {
+		t.Error("replacing with a missing icon succeeded")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	stream, err := Update(testRender, "status", "check", core.IconMini, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<turbo-stream action="update" target="status"><template><svg class="" data-icon="mini/check"></svg></template></turbo-stream>`
+	if string(stream) != want {
+		t.Errorf("got  %s\nwant %s", stream, want)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	rec := httptest.NewRecorder()
+	if err := Write(rec, Stream("remove", "a", ""), Stream("remove", "b", "")); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.Header().Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %q, want %q", got, ContentType)
+	}
+	if got := strings.Count(rec.Body.String(), "<turbo-stream "); got != 2 {
+		t.Errorf("wrote %d streams, want 2:\n%s", got, rec.Body.String())
+	}
+}
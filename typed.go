@@ -0,0 +1,136 @@
+package heroicons
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// typedFuncsFile is the name of the file generated in each typed subpackage
+const typedFuncsFile = "icons.go"
+
+// typedPackageTypes are the icon types that get a typed subpackage
+var typedPackageTypes = []IconType{IconOutline, IconSolid, IconMini, IconMicro}
+
+const typedTemplate = `// Code generated by heroicons generator; DO NOT EDIT.
+
+// Package {{.Type}} provides a render function for each embedded {{.Type}} icon.
+package {{.Type}}
+
+import (
+	"html/template"
+
+	"github.com/patrickward/go-heroicons/core"
+	parent "{{.ImportPath}}"
+)
+{{- range .Funcs }}
+
+// {{ .Ident }} renders the {{ .Name }} icon with the given classes
+func {{ .Ident }}(class string) template.HTML {
+	return parent.Icon("{{ .Name }}", core.{{ $.TypeConst }}, class)
+}
+{{- end }}
+`
+
+// generateTypedFuncs writes a subpackage per icon type with a render function for each
+// icon in the manifest. Subpackages of types without icons are removed.
+func (g *Generator) generateTypedFuncs(iconPaths map[string]string) error {
+	importPath, err := g.importPath()
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("typed").Parse(typedTemplate)
+	if err != nil {
+		return err
+	}
+
+	byType := make(map[IconType]map[string]string)
+	for key, filename := range iconPaths {
+		iconType, _, _ := strings.Cut(key, "/")
+		if byType[IconType(iconType)] == nil {
+			byType[IconType(iconType)] = make(map[string]string)
+		}
+		byType[IconType(iconType)][key] = filename
+	}
+
+	for _, iconType := range typedPackageTypes {
+		dir := string(iconType)
+		// The subpackage only declares the functions, so no identifier is reserved
+		funcs := g.iconIdents(byType[iconType], "", nil, string(iconType)+" function")
+		if len(funcs) == 0 {
+			if err := g.output().RemoveAll(dir + "/" + typedFuncsFile); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := g.output().MkdirAll(dir); err != nil {
+			return err
+		}
+
+		err := g.executeToFile(tmpl, dir+"/"+typedFuncsFile, map[string]any{
+			"Type":       iconType,
+			"TypeConst":  strings.TrimPrefix(iconTypeExpr(iconType), "core."),
+			"ImportPath": importPath,
+			"Funcs":      funcs,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importPath returns the import path of the generated package, using ImportPath if set
+// and otherwise deriving it from the enclosing go.mod
+func (g *Generator) importPath() (string, error) {
+	if g.ImportPath != "" {
+		return g.ImportPath, nil
+	}
+
+	dir, err := filepath.Abs(g.OutputPath)
+	if err != nil {
+		return "", err
+	}
+
+	for root := dir; ; root = filepath.Dir(root) {
+		if module, ok := modulePath(filepath.Join(root, "go.mod")); ok {
+			rel, err := filepath.Rel(root, dir)
+			if err != nil {
+				return "", err
+			}
+			if rel == "." {
+				return module, nil
+			}
+			return module + "/" + filepath.ToSlash(rel), nil
+		}
+		if filepath.Dir(root) == root {
+			return "", fmt.Errorf("cannot determine the import path of %s: no go.mod found; set ImportPath", g.OutputPath)
+		}
+	}
+}
+
+// modulePath returns the module path declared in the go.mod file at path
+func modulePath(path string) (string, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(module), `"`), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,29 @@
+package heroicons
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateTypedFuncsSkipsCollidingNames(t *testing.T) {
+	out := &MemFS{}
+	g := newTestGenerator(testSource(map[string]string{"arrow-up": "arrow", "arrow_up": "arrow too", "1-home": "home"}), out, "arrow-up", "arrow_up", "1-home")
+	g.TypedFuncs = true
+	g.ImportPath = "example.com/app/icons"
+	var log strings.Builder
+	g.Log = &log
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	typed := readOutput(t, out, "outline/icons.go")
+	if n := strings.Count(typed, "func ArrowUp("); n != 1 {
+		t.Errorf("ArrowUp is declared %d times:\n%s", n, typed)
+	}
+	if strings.Contains(typed, "func 1") || strings.Contains(typed, `"1-home"`) {
+		t.Errorf("an invalid identifier was declared:\n%s", typed)
+	}
+	if !containsAll(log.String(), "already used", "does not start with a letter") {
+		t.Errorf("skipped functions were not logged:\n%s", log.String())
+	}
+}
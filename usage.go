@@ -0,0 +1,115 @@
+package heroicons
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IconUsage describes how an icon is used, for design system audits
+type IconUsage struct {
+	Key  string   `json:"key"`
+	Name string   `json:"name"`
+	Type IconType `json:"type"`
+	// Embedded reports whether the icon is in the generated package
+	Embedded bool `json:"embedded"`
+	// CallSites lists the template calls and //heroicons:use comments referencing the
+	// icon, as file:line
+	CallSites []string `json:"call_sites"`
+	// Renders is the number of times the icon was rendered, from the render counts
+	// given to Usage
+	Renders int64 `json:"renders"`
+}
+
+// UsageReport lists the usage of every embedded or referenced icon, sorted by key
+type UsageReport struct {
+	Icons []IconUsage `json:"icons"`
+}
+
+// Usage builds a usage report combining the icons in the generated package, the call
+// sites found in TemplateDirs and AnnotationDirs, and the given render counts, keyed by
+// icon key. Render counts are collected at runtime with the RenderCounts function of the
+// generated package; pass nil if none are available.
+func (g *Generator) Usage(renders map[string]int64) (*UsageReport, error) {
+	usage := make(map[string]*IconUsage)
+	get := func(key string) *IconUsage {
+		if u, ok := usage[key]; ok {
+			return u
+		}
+		iconType, name, _ := strings.Cut(key, "/")
+		u := &IconUsage{Key: key, Name: name, Type: IconType(iconType), CallSites: []string{}}
+		usage[key] = u
+		return u
+	}
+
+	iconPaths, err := existingIconPaths(g.output(), g.iconsDirName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read icons directory: %w", err)
+	}
+	for key := range iconPaths {
+		get(key).Embedded = true
+	}
+
+	var uses []iconUse
+	for _, dir := range g.TemplateDirs {
+		found, err := scanTemplateUses(dir, g.TemplateFuncs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan templates: %w", err)
+		}
+		uses = append(uses, found...)
+	}
+	for _, dir := range g.AnnotationDirs {
+		found, err := scanGoAnnotationUses(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan annotations: %w", err)
+		}
+		uses = append(uses, found...)
+	}
+	for _, use := range uses {
+		u := get(fmt.Sprintf("%s/%s", use.Icon.Type, use.Icon.Name))
+		u.CallSites = append(u.CallSites, fmt.Sprintf("%s:%d", filepath.ToSlash(use.File), use.Line))
+	}
+
+	for key, count := range renders {
+		get(key).Renders = count
+	}
+
+	report := &UsageReport{Icons: make([]IconUsage, 0, len(usage))}
+	for _, u := range usage {
+		report.Icons = append(report.Icons, *u)
+	}
+	sort.Slice(report.Icons, func(i, j int) bool {
+		return report.Icons[i].Key < report.Icons[j].Key
+	})
+	return report, nil
+}
+
+// WriteJSON writes the report as JSON
+func (r *UsageReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes the report as CSV with one row per icon. Call sites are separated by
+// spaces.
+func (r *UsageReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"icon", "type", "embedded", "call_sites", "renders"})
+	for _, u := range r.Icons {
+		_ = cw.Write([]string{
+			u.Name,
+			string(u.Type),
+			strconv.FormatBool(u.Embedded),
+			strings.Join(u.CallSites, " "),
+			strconv.FormatInt(u.Renders, 10),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,46 @@
+package heroicons
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/patrickward/go-heroicons/core"
+)
+
+// unsafeElements are elements that can run script or embed arbitrary HTML when an SVG is
+// rendered inline
+var unsafeElements = []string{"script", "foreignObject", "iframe", "embed", "object"}
+
+// checkSVG checks that the SVG content is well-formed XML with a single <svg> root element
+// and nothing that could run script when it is rendered inline as template.HTML: script
+// and foreignObject elements, event handler attributes, javascript: URLs, document type
+// declarations (which can declare external entities) and processing instructions. Unlike
+// ValidateSVG, any other element is allowed, so custom icons may use gradients and masks.
+func checkSVG(svg []byte) error {
+	return core.WalkSVG(svg, func(token xml.Token) error {
+		switch t := token.(type) {
+		case xml.StartElement:
+			if slices.ContainsFunc(unsafeElements, func(name string) bool { return strings.EqualFold(name, t.Name.Local) }) {
+				return fmt.Errorf("element <%s> is not allowed", t.Name.Local)
+			}
+			for _, attr := range t.Attr {
+				if strings.HasPrefix(strings.ToLower(attr.Name.Local), "on") {
+					return fmt.Errorf("event handler attribute %s on <%s> is not allowed", attr.Name.Local, t.Name.Local)
+				}
+				if value := strings.ToLower(strings.Join(strings.Fields(attr.Value), "")); strings.HasPrefix(value, "javascript:") {
+					return fmt.Errorf("javascript: URL in attribute %s on <%s> is not allowed", attr.Name.Local, t.Name.Local)
+				}
+			}
+		case xml.Directive:
+			return errors.New("document type declarations and entities are not allowed")
+		case xml.ProcInst:
+			if t.Target != "xml" {
+				return fmt.Errorf("processing instruction <?%s?> is not allowed", t.Target)
+			}
+		}
+		return nil
+	})
+}
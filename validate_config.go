@@ -0,0 +1,130 @@
+package heroicons
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"net/url"
+	"os"
+)
+
+// iconTypes lists the valid icon types
+var iconTypes = []IconType{IconOutline, IconSolid, IconMini, IconMicro, IconCustom}
+
+// Validate checks the generator configuration and returns an error describing every
+// problem found, such as unknown icon types, duplicate icons, conflicting options or a
+// missing heroicons repository. The error wraps ErrInvalidConfig. Generate calls Validate
+// before writing any files.
+func (g *Generator) Validate() error {
+	var errs []error
+
+	if g.Source != nil {
+		if g.HeroiconsPath != "" || g.HeroiconsVersion != "" || g.SourceFS != nil {
+			errs = append(errs, errors.New("Source cannot be combined with HeroiconsPath, HeroiconsVersion or SourceFS"))
+		}
+	} else if g.SourceFS != nil {
+		if g.HeroiconsPath != "" || g.HeroiconsVersion != "" {
+			errs = append(errs, errors.New("SourceFS cannot be combined with HeroiconsPath or HeroiconsVersion"))
+		}
+	} else if g.HeroiconsVersion != "" {
+		if g.HeroiconsPath != "" {
+			errs = append(errs, errors.New("HeroiconsPath and HeroiconsVersion cannot both be set: choose a local clone or a downloaded release"))
+		}
+	} else if len(g.Icons) > 0 || len(g.AnnotationDirs) > 0 || len(g.TemplateDirs) > 0 {
+		if g.HeroiconsPath == "" {
+			errs = append(errs, errors.New("HeroiconsPath or HeroiconsVersion is required: set it to the path of your heroicons clone or a release version"))
+		} else if info, err := os.Stat(g.HeroiconsPath); err != nil {
+			errs = append(errs, fmt.Errorf("HeroiconsPath %q cannot be read: %w", g.HeroiconsPath, err))
+		} else if !info.IsDir() {
+			errs = append(errs, fmt.Errorf("HeroiconsPath %q is not a directory", g.HeroiconsPath))
+		}
+	}
+
+	if g.HeroiconsNPM && g.HeroiconsVersion == "" {
+		errs = append(errs, errors.New("HeroiconsNPM requires HeroiconsVersion: set HeroiconsPath to a local npm package instead, such as node_modules/heroicons"))
+	}
+	if (g.HeroiconsMirror != "" || g.HeroiconsProxy != "") && g.HeroiconsVersion == "" {
+		errs = append(errs, errors.New("HeroiconsMirror and HeroiconsProxy require HeroiconsVersion: they only apply to downloaded releases"))
+	}
+	for name, value := range map[string]string{"HeroiconsMirror": g.HeroiconsMirror, "HeroiconsProxy": g.HeroiconsProxy} {
+		if value == "" {
+			continue
+		}
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%s %q is not an absolute URL, such as https://mirror.example.com", name, value))
+		}
+	}
+
+	if g.PackageName != "" && !token.IsIdentifier(g.PackageName) {
+		errs = append(errs, fmt.Errorf("PackageName %q is not a valid Go package name", g.PackageName))
+	}
+
+	errs = append(errs, g.validateLayout()...)
+	errs = append(errs, g.validateSplit()...)
+	errs = append(errs, g.validateBundle()...)
+
+	if g.Workers < 0 {
+		errs = append(errs, fmt.Errorf("Workers must not be negative, got %d", g.Workers))
+	}
+
+	if g.ClearIcons && g.Merge {
+		errs = append(errs, errors.New("ClearIcons and Merge cannot be used together: ClearIcons removes the icons Merge would keep"))
+	}
+
+	for _, source := range g.CustomDirs {
+		if !source.iconType().Valid() {
+			errs = append(errs, fmt.Errorf("custom icons directory %q has unknown type %q (expected one of %v)", source.Path, source.Type, iconTypes))
+		}
+		if info, err := os.Stat(source.Path); err != nil {
+			errs = append(errs, fmt.Errorf("custom icons directory %q cannot be read: %w", source.Path, err))
+		} else if !info.IsDir() {
+			errs = append(errs, fmt.Errorf("custom icons directory %q is not a directory", source.Path))
+		}
+	}
+
+	errs = append(errs, validateIcons(g.Icons)...)
+	errs = append(errs, validatePatterns(g.Icons, "icon")...)
+	errs = append(errs, validatePatterns(g.Exclude, "excluded icon")...)
+	for _, icon := range g.Exclude {
+		if !icon.Type.Valid() {
+			errs = append(errs, fmt.Errorf("excluded icon %q has unknown type %q (expected one of %v)", icon.Name, icon.Type, iconTypes))
+		}
+	}
+
+	for key, replacement := range g.Deprecated {
+		if _, err := ParseIconSet(key); err != nil {
+			errs = append(errs, fmt.Errorf("deprecated icon: %w", err))
+		}
+		if replacement != "" {
+			if _, err := ParseIconSet(replacement); err != nil {
+				errs = append(errs, fmt.Errorf("replacement of deprecated icon %s: %w", key, err))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, errors.Join(errs...))
+	}
+	return nil
+}
+
+// validateIcons checks each icon for a name, a known type and duplicates
+func validateIcons(icons []IconSet) []error {
+	var errs []error
+	seen := make(map[IconSet]bool, len(icons))
+	for i, icon := range icons {
+		if icon.Name == "" {
+			errs = append(errs, fmt.Errorf("icon %d has no name", i))
+		} else if !validIconName(icon.Name) {
+			errs = append(errs, fmt.Errorf("icon %q has a name that cannot be used as a file name", icon.Name))
+		}
+		if !icon.Type.Valid() {
+			errs = append(errs, fmt.Errorf("icon %q has unknown type %q (expected one of %v)", icon.Name, icon.Type, iconTypes))
+		}
+		if seen[icon] {
+			errs = append(errs, fmt.Errorf("icon %s/%s is listed more than once", icon.Type, icon.Name))
+		}
+		seen[icon] = true
+	}
+	return errs
+}
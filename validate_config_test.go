@@ -0,0 +1,67 @@
+package heroicons
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestValidate(t *testing.T) {
+	source := fstest.MapFS{}
+	outline := func(names ...string) []IconSet {
+		var icons []IconSet
+		for _, name := range names {
+			icons = append(icons, IconSet{Name: name, Type: IconOutline})
+		}
+		return icons
+	}
+
+	tests := []struct {
+		name string
+		g    Generator
+		want string // substring of the error, or empty for a valid configuration
+	}{
+		{"valid", Generator{SourceFS: source, Icons: outline("home")}, ""},
+		{"no source", Generator{Icons: outline("home")}, "HeroiconsPath or HeroiconsVersion is required"},
+		{"missing path", Generator{HeroiconsPath: "testdata/does-not-exist", Icons: outline("home")}, "cannot be read"},
+		{"path and version", Generator{HeroiconsPath: ".", HeroiconsVersion: "2.2.0"}, "cannot both be set"},
+		{"source and path", Generator{SourceFS: source, HeroiconsPath: "."}, "SourceFS cannot be combined"},
+		{"npm without version", Generator{SourceFS: source, HeroiconsNPM: true}, "HeroiconsNPM requires HeroiconsVersion"},
+		{"package name", Generator{SourceFS: source, PackageName: "my-icons"}, "not a valid Go package name"},
+		{"unknown type", Generator{SourceFS: source, Icons: []IconSet{{Name: "home", Type: "bold"}}}, `unknown type "bold"`},
+		{"duplicate", Generator{SourceFS: source, Icons: outline("home", "home")}, "listed more than once"},
+		{"unnamed", Generator{SourceFS: source, Icons: outline("")}, "has no name"},
+		{"clear and merge", Generator{SourceFS: source, ClearIcons: true, Merge: true}, "ClearIcons and Merge"},
+		{"split and typed", Generator{SourceFS: source, SplitTypes: true, TypedFuncs: true}, "SplitTypes and TypedFuncs"},
+		{"bundle and build tags", Generator{SourceFS: source, Bundle: BundleZip, BuildTags: true}, "Bundle and BuildTags"},
+		{"negative workers", Generator{SourceFS: source, Workers: -1}, "Workers must not be negative"},
+		{"deprecated key", Generator{SourceFS: source, Deprecated: map[string]string{"home": ""}}, "deprecated icon"},
+	}
+	for _, tt := range tests {
+		err := tt.g.Validate()
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: got %v, want ErrInvalidConfig", tt.name, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %q does not contain %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	g := Generator{SourceFS: fstest.MapFS{}, PackageName: "1icons", Workers: -2, ClearIcons: true, Merge: true}
+	err := g.Validate()
+	for _, want := range []string{"PackageName", "Workers", "ClearIcons and Merge"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("error %v does not mention %s", err, want)
+		}
+	}
+}
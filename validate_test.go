@@ -0,0 +1,36 @@
+package heroicons
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckSVG(t *testing.T) {
+	tests := []struct {
+		svg  string
+		want string // substring of the error, or empty for none
+	}{
+		{`<svg xmlns="http://www.w3.org/2000/svg"><path d="M0 0"/></svg>`, ""},
+		{`<?xml version="1.0"?><svg><linearGradient id="g"/><mask id="m"/></svg>`, ""},
+		{`<svg><path d="M0 0"></svg>`, "malformed SVG"},
+		{`<svg/><svg/>`, "multiple root elements"},
+		{`<g/>`, "root element is <g>"},
+		{`<svg/>text`, "unexpected text"},
+		{``, "missing <svg> root element"},
+		{`<svg><script>alert(1)</script></svg>`, "element <script> is not allowed"},
+		{`<svg><ForeignObject/></svg>`, "element <ForeignObject> is not allowed"},
+		{`<svg onload="alert(1)"/>`, "event handler attribute onload"},
+		{`<svg><a href=" java script:alert(1)"/></svg>`, "javascript: URL"},
+		{`<!DOCTYPE svg [<!ENTITY x "y">]><svg/>`, "document type declarations"},
+		{`<?php echo 1 ?><svg/>`, "processing instruction <?php?>"},
+	}
+	for _, tt := range tests {
+		err := checkSVG([]byte(tt.svg))
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%q: unexpected error: %v", tt.svg, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%q: got %v, want an error containing %q", tt.svg, err, tt.want)
+		}
+	}
+}
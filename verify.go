@@ -0,0 +1,201 @@
+package heroicons
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrOutdated is returned by Verify when the generated output is not up to date
+var ErrOutdated = errors.New("generated output is out of date")
+
+// OutdatedError is the error returned by Verify when the generated output is not up to
+// date. It wraps ErrOutdated.
+type OutdatedError struct {
+	// Files lists the stale files in the output directory
+	Files []string
+	// Leftover lists the generated files in the output directory that generation no
+	// longer writes, and that should be deleted
+	Leftover []string
+}
+
+func (e *OutdatedError) Error() string {
+	var sections []string
+	if len(e.Files) > 0 {
+		sections = append(sections, "regenerate to update:\n"+strings.Join(e.Files, "\n"))
+	}
+	if len(e.Leftover) > 0 {
+		sections = append(sections, "delete the leftover generated files:\n"+strings.Join(e.Leftover, "\n"))
+	}
+	return fmt.Sprintf("%v; %s", ErrOutdated, strings.Join(sections, "\n"))
+}
+
+func (e *OutdatedError) Unwrap() error {
+	return ErrOutdated
+}
+
+// Verify regenerates the output in memory and compares it with the current output,
+// returning an *OutdatedError listing the stale files if regenerating would change
+// anything, and the generated files that regenerating would leave behind. Call it from a test to catch drift between the configuration and the
+// generated code. Post-generate commands and webhooks are not run.
+func (g *Generator) Verify() error {
+	if g.DryRun {
+		return errors.New("Verify cannot be used with DryRun")
+	}
+
+	out := g.output()
+	regenerated := &recordingFS{WriteFS: &MemFS{}, written: make(map[string]bool)}
+	if err := copyFS(regenerated.WriteFS, out); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to copy output: %w", err)
+	}
+
+	clone := *g
+	clone.Output = regenerated
+	clone.PostGenerate = nil
+	clone.Webhook = ""
+
+	if _, err := clone.GenerateReport(); err != nil {
+		return err
+	}
+
+	stale, err := diffFS(out, regenerated)
+	if err != nil {
+		return err
+	}
+	leftover, err := g.leftoverFiles(regenerated)
+	if err != nil {
+		return err
+	}
+	if len(stale) > 0 || len(leftover) > 0 {
+		outdated := &OutdatedError{}
+		for _, name := range stale {
+			outdated.Files = append(outdated.Files, g.outputFile(name))
+		}
+		for _, name := range leftover {
+			outdated.Leftover = append(outdated.Leftover, g.outputFile(name))
+		}
+		return outdated
+	}
+	return nil
+}
+
+// generatedHeader starts every Go file written by the generator
+const generatedHeader = "// Code generated by heroicons generator; DO NOT EDIT."
+
+// generatedArtifacts are the names of the non-Go files written to the output directory
+var generatedArtifacts = []string{ManifestFileName, AttributionFileName, SpriteFileName, CSSFileName, "bundle.zip", "bundle.tar"}
+
+// leftoverFiles returns the names of the generated files in the regenerated output that
+// were kept from the previous output without being written again: Go files marked as
+// generated, the generated artifacts and, unless Merge keeps them, icons. The lockfile is
+// read by generation, so it is never a leftover.
+func (g *Generator) leftoverFiles(regenerated *recordingFS) ([]string, error) {
+	dir := g.iconsDirName()
+	var leftover []string
+	err := fs.WalkDir(regenerated, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || regenerated.wasWritten(name) {
+			return err
+		}
+		base := path.Base(name)
+		switch {
+		case strings.HasSuffix(name, ".go"):
+			content, err := fs.ReadFile(regenerated, name)
+			if err != nil {
+				return err
+			}
+			if bytes.HasPrefix(content, []byte(generatedHeader)) {
+				leftover = append(leftover, name)
+			}
+		case path.Dir(name) == dir && path.Ext(name) == ".svg":
+			if !g.Merge {
+				leftover = append(leftover, name)
+			}
+		case slices.Contains(generatedArtifacts, base):
+			leftover = append(leftover, name)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+	return leftover, err
+}
+
+// recordingFS is a WriteFS recording the names of the files written to it
+type recordingFS struct {
+	WriteFS
+	mu      sync.Mutex
+	written map[string]bool
+}
+
+// WriteFile writes content to the named file and records its name
+func (r *recordingFS) WriteFile(name string, content []byte) error {
+	r.mu.Lock()
+	r.written[name] = true
+	r.mu.Unlock()
+	return r.WriteFS.WriteFile(name, content)
+}
+
+// wasWritten reports whether the named file was written
+func (r *recordingFS) wasWritten(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.written[name]
+}
+
+// copyFS copies the files of src into dst
+func copyFS(dst WriteFS, src fs.FS) error {
+	return fs.WalkDir(src, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return dst.MkdirAll(name)
+		}
+		content, err := fs.ReadFile(src, name)
+		if err != nil {
+			return err
+		}
+		return dst.WriteFile(name, content)
+	})
+}
+
+// diffFS returns the names of the files that differ between current and the regenerated
+// copy, including files that exist in only one of them
+func diffFS(current, regenerated fs.FS) ([]string, error) {
+	files := make(map[string]bool)
+	for _, fsys := range []fs.FS{current, regenerated} {
+		err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) && name == "." {
+					return nil
+				}
+				return err
+			}
+			if !d.IsDir() {
+				files[name] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var stale []string
+	for name := range files {
+		currentContent, currentErr := fs.ReadFile(current, name)
+		expected, expectedErr := fs.ReadFile(regenerated, name)
+		if currentErr != nil || expectedErr != nil || !bytes.Equal(currentContent, expected) {
+			stale = append(stale, name)
+		}
+	}
+	sort.Strings(stale)
+	return stale, nil
+}
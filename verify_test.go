@@ -0,0 +1,100 @@
+package heroicons
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// verifiedGenerator generates home and bell with configure applied into a new MemFS and
+// returns the generator and its output
+func verifiedGenerator(t *testing.T, configure func(g *Generator)) (*Generator, *MemFS) {
+	t.Helper()
+	out := &MemFS{}
+	g := newTestGenerator(testSource(map[string]string{"home": "home", "bell": "bell"}), out, "home", "bell")
+	configure(g)
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	return g, out
+}
+
+// outdated returns the *OutdatedError returned by Verify, failing the test for any other
+// result
+func outdated(t *testing.T, g *Generator) *OutdatedError {
+	t.Helper()
+	var outdated *OutdatedError
+	err := g.Verify()
+	if !errors.As(err, &outdated) || !errors.Is(err, ErrOutdated) {
+		t.Fatalf("Verify() = %v, want an *OutdatedError", err)
+	}
+	return outdated
+}
+
+func TestVerifyUpToDate(t *testing.T) {
+	g, _ := verifiedGenerator(t, func(g *Generator) {
+		g.WriteManifest = true
+		g.TypedFuncs = true
+	})
+	if err := g.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyReportsStaleFiles(t *testing.T) {
+	g, out := verifiedGenerator(t, func(g *Generator) {})
+	if err := out.WriteFile("icons/outline_home.svg", []byte("<svg>edited</svg>")); err != nil {
+		t.Fatal(err)
+	}
+	g.Icons = g.Icons[:1]
+
+	got := outdated(t, g)
+	want := []string{filepath.Join("icons", "icons", "outline_home.svg"), filepath.Join("icons", defaultProviderFile)}
+	if !slices.Equal(got.Files, want) {
+		t.Errorf("Files = %v, want %v", got.Files, want)
+	}
+	if !slices.Equal(got.Leftover, []string{filepath.Join("icons", "icons", "outline_bell.svg")}) {
+		t.Errorf("Leftover = %v, want the dropped icon", got.Leftover)
+	}
+}
+
+func TestVerifyReportsRemovedFiles(t *testing.T) {
+	g, _ := verifiedGenerator(t, func(g *Generator) { g.LinkedFuncs = true })
+	g.LinkedFuncs = false
+
+	got := outdated(t, g)
+	if !slices.Contains(got.Files, filepath.Join("icons", linkedFuncsFile)) {
+		t.Errorf("Files = %v, want %s, which generation removes", got.Files, linkedFuncsFile)
+	}
+}
+
+func TestVerifyReportsLeftoverFiles(t *testing.T) {
+	g, out := verifiedGenerator(t, func(g *Generator) {
+		g.WriteManifest = true
+		g.GenerateTests = true
+	})
+	if err := out.WriteFile("extra.go", []byte("package icons\n")); err != nil {
+		t.Fatal(err)
+	}
+	g.WriteManifest = false
+	g.GenerateTests = false
+
+	got := outdated(t, g)
+	if len(got.Files) != 0 {
+		t.Errorf("Files = %v, want none", got.Files)
+	}
+	want := []string{filepath.Join("icons", ManifestFileName), filepath.Join("icons", g.providerTestFile())}
+	if !slices.Equal(got.Leftover, want) {
+		t.Errorf("Leftover = %v, want %v", got.Leftover, want)
+	}
+
+	// Icons kept by Merge are part of the package
+	g.Icons = g.Icons[:1]
+	g.WriteManifest = true
+	g.GenerateTests = true
+	g.Merge = true
+	if err := g.Verify(); err != nil {
+		t.Errorf("Verify() with Merge = %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package heroicons
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultWatchInterval is how often Watch checks for changes when no interval is given
+const DefaultWatchInterval = 500 * time.Millisecond
+
+// Watch generates the icons and then regenerates them whenever the config file or the
+// scanned template and annotation directories change, until ctx is cancelled. If the
+// generator was configured with LoadConfig, the config file is reloaded when it changes.
+// Changes are detected by polling every interval.
+func (g *Generator) Watch(ctx context.Context, interval time.Duration) error {
+	return Watch(ctx, interval, func() (*Generator, error) {
+		if g.configPath != "" {
+			if err := g.LoadConfig(g.configPath); err != nil {
+				return nil, err
+			}
+		}
+		return g, nil
+	})
+}
+
+// Watch calls load to configure a generator, generates the icons, and then regenerates
+// them whenever the generator's config file or scanned template and annotation
+// directories change, until ctx is cancelled. load is called again before each
+// regeneration, so it can re-read configuration. When the only change is newly referenced
+// icons, just those icons are copied (see GenerateIcons); otherwise the whole output is
+// regenerated. Errors after the initial generation are logged and watching continues.
+func Watch(ctx context.Context, interval time.Duration, load func() (*Generator, error)) error {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	g, err := load()
+	if err != nil {
+		return err
+	}
+	if err := g.GenerateContext(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
+	icons, err := g.resolveIcons()
+	if err != nil {
+		return err
+	}
+	state := g.watchState()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		next := g.watchState()
+		if maps.Equal(state, next) {
+			continue
+		}
+		fullRegen := g.configPath != "" && state[g.configPath] != next[g.configPath] ||
+			g.customChanged(state, next)
+		state = next
+
+		loaded, err := load()
+		if err != nil {
+			g.logf("Failed to load configuration: %v\n", err)
+			continue
+		}
+		g = loaded
+		state = g.watchState()
+
+		resolved, err := g.regenerate(ctx, icons, fullRegen)
+		if err != nil {
+			g.logf("Failed to regenerate icons: %v\n", err)
+			continue
+		}
+		icons = resolved
+	}
+}
+
+// regenerate brings the output up to date after a change. If full is set (the
+// configuration or a custom icon changed) or icons were removed, everything is
+// regenerated; if icons were only added, just the new icons are copied. It returns the
+// icons now included.
+func (g *Generator) regenerate(ctx context.Context, previous []IconSet, full bool) ([]IconSet, error) {
+	icons, err := g.resolveIcons()
+	if err != nil {
+		return nil, err
+	}
+
+	added := subtractIcons(icons, previous)
+	removed := subtractIcons(previous, icons)
+
+	switch {
+	case full || len(removed) > 0:
+		if err := g.GenerateContext(ctx); err != nil {
+			return nil, err
+		}
+		g.logf("Regenerated icons\n")
+	case len(added) > 0:
+		if err := g.GenerateIcons(added...); err != nil {
+			return nil, err
+		}
+		g.logf("Added %d icon(s)\n", len(added))
+	}
+
+	return icons, nil
+}
+
+// subtractIcons returns the icons in a that are not in b
+func subtractIcons(a, b []IconSet) []IconSet {
+	exclude := make(map[IconSet]bool, len(b))
+	for _, icon := range b {
+		exclude[icon] = true
+	}
+
+	var result []IconSet
+	for _, icon := range a {
+		if !exclude[icon] {
+			result = append(result, icon)
+		}
+	}
+	return result
+}
+
+// customChanged reports whether a file in one of the custom icon directories was added,
+// removed or modified between two watch states. Custom icons are not matched against the
+// resolved icon list, so any such change needs a full regeneration.
+func (g *Generator) customChanged(before, after map[string]string) bool {
+	inCustomDir := func(path string) bool {
+		for _, source := range g.CustomDirs {
+			if rel, err := filepath.Rel(source.Path, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for path, stamp := range after {
+		if before[path] != stamp && inCustomDir(path) {
+			return true
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok && inCustomDir(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// watchState records the modification time and size of the config file and every file
+// in the scanned directories
+func (g *Generator) watchState() map[string]string {
+	state := make(map[string]string)
+	record := func(path string) error {
+		if info, err := os.Stat(path); err == nil {
+			state[path] = fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+		}
+		return nil
+	}
+
+	if g.configPath != "" {
+		_ = record(g.configPath)
+	}
+	for _, dir := range g.AnnotationDirs {
+		_ = walkSource(dir, []string{".go"}, record)
+	}
+	for _, dir := range g.TemplateDirs {
+		_ = walkSource(dir, templateExts, record)
+	}
+	for _, source := range g.CustomDirs {
+		_ = walkSource(source.Path, []string{".svg"}, record)
+	}
+	return state
+}
@@ -0,0 +1,33 @@
+package heroicons
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCustomChanged(t *testing.T) {
+	dir := filepath.Join("assets", "icons")
+	g := &Generator{CustomDirs: []CustomSource{{Path: dir}}}
+
+	logo := filepath.Join(dir, "logo.svg")
+	tmpl := filepath.Join("templates", "page.html")
+	before := map[string]string{logo: "1/10", tmpl: "1/20"}
+
+	tests := []struct {
+		name  string
+		after map[string]string
+		want  bool
+	}{
+		{"unchanged", map[string]string{logo: "1/10", tmpl: "1/20"}, false},
+		{"template changed", map[string]string{logo: "1/10", tmpl: "2/20"}, false},
+		{"custom icon changed", map[string]string{logo: "2/10", tmpl: "1/20"}, true},
+		{"custom icon added", map[string]string{logo: "1/10", tmpl: "1/20", filepath.Join(dir, "brand", "mark.svg"): "1/5"}, true},
+		{"custom icon removed", map[string]string{tmpl: "1/20"}, true},
+		{"sibling directory", map[string]string{logo: "1/10", tmpl: "1/20", filepath.Join("assets", "icons2", "x.svg"): "1/5"}, false},
+	}
+	for _, tt := range tests {
+		if got := g.customChanged(before, tt.after); got != tt.want {
+			t.Errorf("%s: customChanged = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
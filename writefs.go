@@ -0,0 +1,414 @@
+package heroicons
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WriteFS is a file system the generated output is written to. Names are slash-separated
+// paths relative to the output directory, as with fs.FS, which is used to read back
+// previously generated output.
+type WriteFS interface {
+	fs.FS
+	// WriteFile writes content to the named file, creating or replacing it. The parent
+	// directory must exist.
+	WriteFile(name string, content []byte) error
+	// MkdirAll creates the named directory along with any missing parents
+	MkdirAll(name string) error
+	// RemoveAll removes the named file, or directory and everything it contains. It
+	// returns nil if the name does not exist.
+	RemoveAll(name string) error
+}
+
+// DirFS returns a WriteFS for the directory dir on disk. It is used by default, for
+// Generator.OutputPath.
+func DirFS(dir string) WriteFS {
+	return newDirFS(dir, false)
+}
+
+// newDirFS returns a dirFS for dir. The root is made absolute, so Go's Windows long path
+// handling, which adds the \\?\ prefix to long absolute paths, applies to every file
+// written, whatever the working directory.
+func newDirFS(dir string, sync bool) dirFS {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return dirFS{root: dir, sync: sync}
+}
+
+// dirFS is a WriteFS for a directory on disk
+type dirFS struct {
+	root string
+	// sync flushes written files to stable storage (see Generator.Sync)
+	sync bool
+}
+
+// path returns the path on disk of the named file
+func (d dirFS) path(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(d.root, filepath.FromSlash(name)), nil
+}
+
+// Open opens the named file for reading
+func (d dirFS) Open(name string) (fs.File, error) {
+	return os.DirFS(d.root).Open(name)
+}
+
+// MkdirAll creates the named directory along with any missing parents
+func (d dirFS) MkdirAll(name string) error {
+	path, err := d.path("mkdir", name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(path, 0755)
+}
+
+// RemoveAll removes the named file, or directory and everything it contains
+func (d dirFS) RemoveAll(name string) error {
+	path, err := d.path("remove", name)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(path)
+}
+
+// WriteFile writes content to the named file through a temporary file in the same
+// directory, which then replaces it. Readers never see a partially written file, a
+// symlink is replaced rather than written through, and the file gets 0644 permissions
+// whatever the permissions of the file it replaces. The written length is verified, and
+// with sync the file and its directory are flushed to stable storage.
+func (d dirFS) WriteFile(name string, content []byte) (err error) {
+	path, err := d.path("writefile", name)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(tmp)
+		}
+	}()
+
+	n, err := f.Write(content)
+	if err != nil {
+		return err
+	}
+	if n != len(content) {
+		return fmt.Errorf("failed to write %s: %w", path, io.ErrShortWrite)
+	}
+	if d.sync {
+		if err := f.Sync(); err != nil {
+			return fmt.Errorf("failed to sync %s: %w", path, err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp, 0644); err != nil {
+		return err
+	}
+	if info, err := os.Stat(tmp); err != nil {
+		return err
+	} else if info.Size() != int64(len(content)) {
+		return fmt.Errorf("failed to write %s: wrote %d of %d bytes", path, info.Size(), len(content))
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	if d.sync {
+		return syncDir(dir)
+	}
+	return nil
+}
+
+// syncDir flushes the directory entries of dir, so a renamed file survives a crash.
+// Directories cannot be synced on Windows, where renames are durable once they return.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	defer func(d *os.File) {
+		_ = d.Close()
+	}(d)
+
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("failed to sync %s: %w", dir, err)
+	}
+	return nil
+}
+
+// isRegularFile reports whether path is a regular file, following symlinks
+func isRegularFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// MemFS is an in-memory WriteFS, for testing generators and for build systems that
+// cannot write to disk. The zero value is an empty file system. It is safe for
+// concurrent use.
+type MemFS struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+	dirs  map[string]bool
+}
+
+// Open opens the named file for reading. The returned file reflects the contents at the
+// time of the call.
+func (m *MemFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if content, ok := m.files[name]; ok {
+		// Written content is replaced, never modified, so the file can share it
+		info := memFileInfo{name: path.Base(name), size: int64(len(content)), mode: 0644}
+		return &memFile{info: info, Reader: bytes.NewReader(content)}, nil
+	}
+	if name != "." && !m.dirs[name] {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	var entries []fs.DirEntry
+	for file, content := range m.files {
+		if path.Dir(file) == name {
+			entries = append(entries, fs.FileInfoToDirEntry(memFileInfo{name: path.Base(file), size: int64(len(content)), mode: 0644}))
+		}
+	}
+	for dir := range m.dirs {
+		if path.Dir(dir) == name {
+			entries = append(entries, fs.FileInfoToDirEntry(memFileInfo{name: path.Base(dir), mode: fs.ModeDir | 0755}))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return &memDir{info: memFileInfo{name: path.Base(name), mode: fs.ModeDir | 0755}, entries: entries}, nil
+}
+
+// memFileInfo describes a file or directory of a MemFS
+type memFileInfo struct {
+	name string
+	size int64
+	mode fs.FileMode
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) Mode() fs.FileMode  { return i.mode }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i memFileInfo) Sys() any           { return nil }
+
+// memFile is an open file of a MemFS
+type memFile struct {
+	*bytes.Reader
+	info memFileInfo
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+// memDir is an open directory of a MemFS
+type memDir struct {
+	info    memFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir returns the next n entries of the directory, or all remaining entries if n <= 0
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(remaining))
+	d.offset += n
+	return remaining[:n], nil
+}
+
+// WriteFile writes content to the named file. The parent directory must exist.
+func (m *MemFS) WriteFile(name string, content []byte) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "writefile", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if dir := path.Dir(name); dir != "." && !m.dirs[dir] {
+		return &fs.PathError{Op: "writefile", Path: name, Err: fs.ErrNotExist}
+	}
+	if m.dirs[name] {
+		return &fs.PathError{Op: "writefile", Path: name, Err: fs.ErrExist}
+	}
+	if m.files == nil {
+		m.files = make(map[string][]byte)
+	}
+	m.files[name] = append([]byte(nil), content...)
+	return nil
+}
+
+// MkdirAll creates the named directory along with any missing parents
+func (m *MemFS) MkdirAll(name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.dirs == nil {
+		m.dirs = make(map[string]bool)
+	}
+	for dir := name; dir != "."; dir = path.Dir(dir) {
+		if _, ok := m.files[dir]; ok {
+			return &fs.PathError{Op: "mkdir", Path: dir, Err: fs.ErrExist}
+		}
+		m.dirs[dir] = true
+	}
+	return nil
+}
+
+// RemoveAll removes the named file, or directory and everything it contains
+func (m *MemFS) RemoveAll(name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+	for file := range m.files {
+		if file == name || len(file) > len(prefix) && file[:len(prefix)] == prefix {
+			delete(m.files, file)
+		}
+	}
+	for dir := range m.dirs {
+		if dir == name || len(dir) > len(prefix) && dir[:len(prefix)] == prefix {
+			delete(m.dirs, dir)
+		}
+	}
+	return nil
+}
+
+// Files returns the names of the files in the file system, sorted
+func (m *MemFS) Files() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// output returns the file system the output is written to: Output if set, or else the
+// directory OutputPath on disk
+func (g *Generator) output() WriteFS {
+	if g.Output != nil {
+		return g.Output
+	}
+	return newDirFS(g.OutputPath, g.Sync)
+}
+
+// subFS returns a WriteFS for the directory dir of out
+func subFS(out WriteFS, dir string) WriteFS {
+	return subWriteFS{parent: out, dir: dir}
+}
+
+// subWriteFS is a WriteFS for a directory of another WriteFS
+type subWriteFS struct {
+	parent WriteFS
+	dir    string
+}
+
+// name returns the name in the parent file system of the named file
+func (s subWriteFS) name(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return path.Join(s.dir, name), nil
+}
+
+// Open opens the named file for reading
+func (s subWriteFS) Open(name string) (fs.File, error) {
+	full, err := s.name("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return s.parent.Open(full)
+}
+
+// WriteFile writes content to the named file
+func (s subWriteFS) WriteFile(name string, content []byte) error {
+	full, err := s.name("writefile", name)
+	if err != nil {
+		return err
+	}
+	return s.parent.WriteFile(full, content)
+}
+
+// MkdirAll creates the named directory along with any missing parents
+func (s subWriteFS) MkdirAll(name string) error {
+	full, err := s.name("mkdir", name)
+	if err != nil {
+		return err
+	}
+	return s.parent.MkdirAll(full)
+}
+
+// RemoveAll removes the named file, or directory and everything it contains
+func (s subWriteFS) RemoveAll(name string) error {
+	full, err := s.name("remove", name)
+	if err != nil {
+		return err
+	}
+	return s.parent.RemoveAll(full)
+}
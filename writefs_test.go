@@ -0,0 +1,78 @@
+package heroicons
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMemFS(t *testing.T) {
+	m := &MemFS{}
+
+	if err := m.WriteFile("icons/home.svg", []byte("<svg/>")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("writing without a parent directory: got %v, want fs.ErrNotExist", err)
+	}
+	if err := m.MkdirAll("icons/outline"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"provider.go", "icons/home.svg", "icons/outline/bell.svg"} {
+		if err := m.WriteFile(name, []byte(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.WriteFile("icons", nil); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("writing over a directory: got %v, want fs.ErrExist", err)
+	}
+	if err := m.MkdirAll("provider.go/x"); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("creating a directory below a file: got %v, want fs.ErrExist", err)
+	}
+	if err := m.WriteFile("../escape", nil); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("writing an invalid path: got %v, want fs.ErrInvalid", err)
+	}
+
+	if err := fstest.TestFS(m, "provider.go", "icons/home.svg", "icons/outline/bell.svg"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.RemoveAll("icons/outline"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.Files(), []string{"icons/home.svg", "provider.go"}; !slices.Equal(got, want) {
+		t.Errorf("files after RemoveAll = %v, want %v", got, want)
+	}
+	if _, err := fs.Stat(m, "icons/outline"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("removed directory still exists: %v", err)
+	}
+}
+
+func TestMemFSConcurrent(t *testing.T) {
+	m := &MemFS{}
+	if err := m.MkdirAll("icons"); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := fmt.Sprintf("icons/%d.svg", i)
+			if err := m.WriteFile(name, []byte(name)); err != nil {
+				t.Error(err)
+			}
+			if _, err := fs.ReadDir(m, "icons"); err != nil {
+				t.Error(err)
+			}
+			_ = m.Files()
+		}()
+	}
+	wg.Wait()
+
+	if got := len(m.Files()); got != 8 {
+		t.Errorf("got %d files, want 8", got)
+	}
+}